@@ -0,0 +1,186 @@
+package hie
+
+// NewBag creates a bag with the provided values
+func NewBag[T comparable](values ...T) *Bag[T] {
+	b := &Bag[T]{}
+	for _, v := range values {
+		b.Add(v)
+	}
+	return b
+}
+
+// BagFromIter creates a bag with the elements of the iterator
+func BagFromIter[T comparable](iter Iter[T]) *Bag[T] {
+	b := &Bag[T]{}
+	for iter.HasNext() {
+		b.Add(iter.Next())
+	}
+	return b
+}
+
+// Bag is a multiset, it counts how many times each value was added. The zero value is an empty bag.
+// Its set operations respect the multiplicities of the values, unlike the distinct semantics of Set.
+type Bag[T comparable] struct {
+	counts map[T]int
+	size   int
+}
+
+// Add adds one occurrence of the value
+func (b *Bag[T]) Add(value T) {
+	b.AddN(value, 1)
+}
+
+// AddN adds n occurrences of the value, n must not be negative
+func (b *Bag[T]) AddN(value T, n int) {
+	if n < 0 {
+		panic("the number of occurrences can't be negative")
+	}
+	if n == 0 {
+		return
+	}
+	if b.counts == nil {
+		b.counts = make(map[T]int)
+	}
+	b.counts[value] += n
+	b.size += n
+}
+
+// Remove removes one occurrence of the value, it returns false when the value was not in the bag
+func (b *Bag[T]) Remove(value T) bool {
+	return b.RemoveN(value, 1) == 1
+}
+
+// RemoveN removes up to n occurrences of the value and returns how many were removed
+func (b *Bag[T]) RemoveN(value T, n int) int {
+	count := b.counts[value]
+	if n > count {
+		n = count
+	}
+	if n <= 0 {
+		return 0
+	}
+	b.setCount(value, count-n)
+	return n
+}
+
+// RemoveAll removes all occurrences of the value and returns how many were removed
+func (b *Bag[T]) RemoveAll(value T) int {
+	return b.RemoveN(value, b.counts[value])
+}
+
+// Count returns the number of occurrences of the value
+func (b *Bag[T]) Count(value T) int {
+	return b.counts[value]
+}
+
+// Has returns true when the bag contains at least one occurrence of the value
+func (b *Bag[T]) Has(value T) bool {
+	return b.counts[value] > 0
+}
+
+// Len returns the number of values in the bag, counting every occurrence
+func (b *Bag[T]) Len() int {
+	return b.size
+}
+
+// Distinct returns the number of different values in the bag
+func (b *Bag[T]) Distinct() int {
+	return len(b.counts)
+}
+
+// Clone returns a copy of the bag
+func (b *Bag[T]) Clone() *Bag[T] {
+	res := &Bag[T]{counts: make(map[T]int, len(b.counts)), size: b.size}
+	for v, n := range b.counts {
+		res.counts[v] = n
+	}
+	return res
+}
+
+// AsIter returns a clonable iterator over a snapshot of the bag, every value is repeated as often as it occurs
+func (b *Bag[T]) AsIter() Iter[T] {
+	values := make([]T, 0, b.size)
+	for v, n := range b.counts {
+		for i := 0; i < n; i++ {
+			values = append(values, v)
+		}
+	}
+	return Slice(values...).AsIter()
+}
+
+// Entries returns a clonable iterator over a snapshot of the distinct values with their number of occurrences
+func (b *Bag[T]) Entries() Iter[Entry[T, int]] {
+	entries := make([]Entry[T, int], 0, len(b.counts))
+	for v, n := range b.counts {
+		entries = append(entries, Entry[T, int]{Key: v, Value: n})
+	}
+	return Slice(entries...).AsIter()
+}
+
+// Equal returns true when both bags contain the same values with the same multiplicities
+func (b *Bag[T]) Equal(other *Bag[T]) bool {
+	if b.size != other.size || len(b.counts) != len(other.counts) {
+		return false
+	}
+	for v, n := range b.counts {
+		if other.counts[v] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// Sum returns a new bag with the occurrences of both bags added up
+func (b *Bag[T]) Sum(other *Bag[T]) *Bag[T] {
+	res := b.Clone()
+	for v, n := range other.counts {
+		res.AddN(v, n)
+	}
+	return res
+}
+
+// Union returns a new bag in which every value occurs as often as in the bag where it occurs most
+func (b *Bag[T]) Union(other *Bag[T]) *Bag[T] {
+	res := b.Clone()
+	for v, n := range other.counts {
+		if n > res.counts[v] {
+			res.setCount(v, n)
+		}
+	}
+	return res
+}
+
+// Intersect returns a new bag in which every value occurs as often as in the bag where it occurs least
+func (b *Bag[T]) Intersect(other *Bag[T]) *Bag[T] {
+	res := &Bag[T]{}
+	for v, n := range b.counts {
+		if m := other.counts[v]; m < n {
+			n = m
+		}
+		res.AddN(v, n)
+	}
+	return res
+}
+
+// Difference returns a new bag with the occurrences of the other bag taken away from this bag
+func (b *Bag[T]) Difference(other *Bag[T]) *Bag[T] {
+	res := &Bag[T]{}
+	for v, n := range b.counts {
+		if n > other.counts[v] {
+			res.AddN(v, n-other.counts[v])
+		}
+	}
+	return res
+}
+
+func (b *Bag[T]) setCount(value T, count int) {
+	if b.counts == nil {
+		b.counts = make(map[T]int)
+	}
+	b.size += count - b.counts[value]
+	if count == 0 {
+		delete(b.counts, value)
+		return
+	}
+	b.counts[value] = count
+}
@@ -0,0 +1,58 @@
+package hie
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sortedBag(b *Bag[string]) []string {
+	values := collect(b.AsIter())
+	sort.Strings(values)
+	return values
+}
+
+func TestBag(t *testing.T) {
+	var b Bag[string]
+	require.False(t, b.Remove("a"))
+	require.Zero(t, b.Len())
+
+	b.Add("a")
+	b.AddN("b", 3)
+	b.AddN("c", 0)
+	require.Equal(t, 4, b.Len())
+	require.Equal(t, 2, b.Distinct())
+	require.Equal(t, 3, b.Count("b"))
+	require.False(t, b.Has("c"))
+	require.Equal(t, []string{"a", "b", "b", "b"}, sortedBag(&b))
+
+	require.True(t, b.Remove("b"))
+	require.Equal(t, 1, b.RemoveN("a", 5))
+	require.Equal(t, 2, b.Len())
+	require.False(t, b.Has("a"))
+	require.Equal(t, []Entry[string, int]{{"b", 2}}, collect(b.Entries()))
+	require.Equal(t, 2, b.RemoveAll("b"))
+	require.Zero(t, b.Len())
+	require.Zero(t, b.Distinct())
+
+	require.Panics(t, func() { b.AddN("a", -1) })
+	require.True(t, BagFromIter(Slice("x", "y", "x").AsIter()).Equal(NewBag("y", "x", "x")))
+	require.False(t, NewBag("x").Equal(NewBag("x", "x")))
+}
+
+func TestBag_Algebra(t *testing.T) {
+	a := NewBag("x", "x", "x", "y")
+	b := NewBag("x", "y", "y", "z")
+
+	require.Equal(t, []string{"x", "x", "x", "x", "y", "y", "y", "z"}, sortedBag(a.Sum(b)))
+	require.Equal(t, []string{"x", "x", "x", "y", "y", "z"}, sortedBag(a.Union(b)))
+	require.Equal(t, []string{"x", "y"}, sortedBag(a.Intersect(b)))
+	require.Equal(t, []string{"x", "x"}, sortedBag(a.Difference(b)))
+	require.Equal(t, []string{"y", "z"}, sortedBag(b.Difference(a)))
+
+	require.Equal(t, 4, a.Len())
+	require.Equal(t, 4, b.Len())
+	require.Equal(t, 8, a.Sum(b).Len())
+	require.Equal(t, 2, a.Intersect(b).Distinct())
+}
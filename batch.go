@@ -0,0 +1,98 @@
+package hie
+
+import (
+	"context"
+	"time"
+
+	"github.com/casualjim/hie/clock"
+)
+
+// BatchTimeout groups the values received from the channel into batches.
+// A batch is emitted when it contains maxSize values or when maxWait has elapsed since its first value arrived.
+// The iterator ends when the channel is closed or the context is done, after emitting the values that were still pending.
+// An optional clock can be provided to control the passing of time, it defaults to the wall clock.
+func BatchTimeout[T any](ctx context.Context, ch <-chan T, maxSize int, maxWait time.Duration, clk ...clock.Clock) Iter[[]T] {
+	if maxSize < 1 {
+		panic("the batch size must be at least 1")
+	}
+	return &batchIter[T]{
+		ctx:     ctx,
+		ch:      ch,
+		maxSize: maxSize,
+		maxWait: maxWait,
+		clock:   clock.Or(clk...),
+	}
+}
+
+type batchIter[T any] struct {
+	ctx     context.Context
+	ch      <-chan T
+	maxSize int
+	maxWait time.Duration
+	clock   clock.Clock
+
+	pending []T
+	done    bool
+}
+
+func (b *batchIter[T]) HasNext() bool {
+	if b.pending != nil {
+		return true
+	}
+	if b.done || b.ch == nil {
+		return false
+	}
+
+	var first T
+	select {
+	case <-b.ctx.Done():
+		b.done = true
+		return false
+	case val, ok := <-b.ch:
+		if !ok {
+			b.done = true
+			return false
+		}
+		first = val
+	}
+
+	batch := make([]T, 1, b.maxSize)
+	batch[0] = first
+	if b.maxSize == 1 {
+		b.pending = batch
+		return true
+	}
+
+	timer := b.clock.NewTimer(b.maxWait)
+	defer timer.Stop()
+
+	for len(batch) < b.maxSize {
+		select {
+		case <-b.ctx.Done():
+			b.done = true
+			b.pending = batch
+			return true
+		case <-timer.C():
+			b.pending = batch
+			return true
+		case val, ok := <-b.ch:
+			if !ok {
+				b.done = true
+				b.pending = batch
+				return true
+			}
+			batch = append(batch, val)
+		}
+	}
+	b.pending = batch
+	return true
+}
+
+func (b *batchIter[T]) Next() []T {
+	if !b.HasNext() {
+		panic("iterating beyond end")
+	}
+	batch := b.pending
+	b.pending = nil
+	return batch
+}
@@ -0,0 +1,81 @@
+package hie
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/casualjim/hie/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTimeout_Size(t *testing.T) {
+	ch := make(chan int, 10)
+	for i := 1; i <= 5; i++ {
+		ch <- i
+	}
+	close(ch)
+
+	it := BatchTimeout(context.Background(), ch, 2, time.Hour, clock.NewManual(time.Now()))
+	require.True(t, it.HasNext())
+	require.Equal(t, []int{1, 2}, it.Next())
+	require.True(t, it.HasNext())
+	require.Equal(t, []int{3, 4}, it.Next())
+	require.True(t, it.HasNext())
+	require.Equal(t, []int{5}, it.Next())
+	require.False(t, it.HasNext())
+	require.Panics(t, func() { it.Next() })
+}
+
+func TestBatchTimeout_MaxWait(t *testing.T) {
+	clk := clock.NewManual(time.Now())
+	ch := make(chan int) // unbuffered so every send is received before the clock moves
+
+	it := BatchTimeout(context.Background(), ch, 10, time.Second, clk)
+	batches := make(chan []int)
+	go func() {
+		for it.HasNext() {
+			batches <- it.Next()
+		}
+		close(batches)
+	}()
+
+	ch <- 1
+	ch <- 2
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	require.Equal(t, []int{1, 2}, <-batches)
+
+	ch <- 3
+	clk.BlockUntil(1)
+	clk.Advance(500 * time.Millisecond)
+	ch <- 4
+	clk.Advance(500 * time.Millisecond)
+	require.Equal(t, []int{3, 4}, <-batches)
+
+	close(ch)
+	_, open := <-batches
+	require.False(t, open)
+}
+
+func TestBatchTimeout_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	clk := clock.NewManual(time.Now())
+	ch := make(chan int, 10)
+	ch <- 1
+
+	it := BatchTimeout(ctx, ch, 10, time.Second, clk)
+	batches := make(chan []int)
+	go func() {
+		for it.HasNext() {
+			batches <- it.Next()
+		}
+		close(batches)
+	}()
+
+	clk.BlockUntil(1)
+	cancel()
+	require.Equal(t, []int{1}, <-batches)
+	_, open := <-batches
+	require.False(t, open)
+}
@@ -0,0 +1,66 @@
+// Package clock abstracts the passing of time so that code depending on timers can be tested
+// deterministically with a manually advanced clock instead of sleeping.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and creates timers
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the equivalent of a time.Timer for a Clock
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Real returns a clock backed by the time package
+func Real() Clock { return realClock{} }
+
+// Or returns the first clock provided or the real clock when none is provided.
+func Or(clk ...Clock) Clock {
+	if len(clk) > 1 {
+		panic("only 1 clock can be specified")
+	}
+	if len(clk) == 0 || clk[0] == nil {
+		return Real()
+	}
+	return clk[0]
+}
+
+// Sleep pauses for the duration on the provided clock, it returns early with the context error when the context is done.
+func Sleep(ctx context.Context, clk Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := clk.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C():
+		return nil
+	}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{t: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (r *realTimer) C() <-chan time.Time        { return r.t.C }
+func (r *realTimer) Stop() bool                 { return r.t.Stop() }
+func (r *realTimer) Reset(d time.Duration) bool { return r.t.Reset(d) }
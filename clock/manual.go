@@ -0,0 +1,142 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// NewManual creates a clock that only moves when it is advanced
+func NewManual(now time.Time) *Manual {
+	m := &Manual{now: now}
+	m.cond = sync.NewCond(&m.mu)
+	return m
+}
+
+// Manual is a Clock whose time only changes through Advance or Set.
+// Timers fire when the clock is moved to or beyond their deadline.
+type Manual struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*manualTimer
+}
+
+func (m *Manual) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+func (m *Manual) NewTimer(d time.Duration) Timer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := &manualTimer{
+		clock: m,
+		c:     make(chan time.Time, 1),
+	}
+	m.schedule(t, d)
+	return t
+}
+
+// Advance moves the clock forward by the duration and fires the timers that are due
+func (m *Manual) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.moveTo(m.now.Add(d))
+}
+
+// Set moves the clock to the provided time and fires the timers that are due
+func (m *Manual) Set(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.moveTo(now)
+}
+
+// Waiters returns the number of timers that are waiting to fire
+func (m *Manual) Waiters() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.timers)
+}
+
+// BlockUntil blocks until at least n timers are waiting to fire.
+// This allows a test to wait for the code under test to arm its timers before advancing the clock.
+func (m *Manual) BlockUntil(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for len(m.timers) < n {
+		m.cond.Wait()
+	}
+}
+
+func (m *Manual) moveTo(now time.Time) {
+	if now.Before(m.now) {
+		return
+	}
+	m.now = now
+
+	pending := m.timers[:0]
+	for _, t := range m.timers {
+		if !t.deadline.After(now) {
+			t.fire(now)
+			continue
+		}
+		pending = append(pending, t)
+	}
+	for i := len(pending); i < len(m.timers); i++ {
+		m.timers[i] = nil
+	}
+	m.timers = pending
+	m.cond.Broadcast()
+}
+
+func (m *Manual) schedule(t *manualTimer, d time.Duration) {
+	if d <= 0 {
+		t.fire(m.now)
+		return
+	}
+	t.deadline = m.now.Add(d)
+	m.timers = append(m.timers, t)
+	m.cond.Broadcast()
+}
+
+func (m *Manual) unschedule(t *manualTimer) bool {
+	for i, pending := range m.timers {
+		if pending == t {
+			m.timers = append(m.timers[:i], m.timers[i+1:]...)
+			m.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+type manualTimer struct {
+	clock    *Manual
+	c        chan time.Time
+	deadline time.Time
+}
+
+func (t *manualTimer) fire(now time.Time) {
+	select {
+	case t.c <- now:
+	default:
+	}
+}
+
+func (t *manualTimer) C() <-chan time.Time { return t.c }
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.unschedule(t)
+}
+
+func (t *manualTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.clock.unschedule(t)
+	t.clock.schedule(t, d)
+	return active
+}
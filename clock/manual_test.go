@@ -0,0 +1,66 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManual_Timer(t *testing.T) {
+	start := time.Date(2022, 11, 20, 0, 0, 0, 0, time.UTC)
+	clk := NewManual(start)
+	require.Equal(t, start, clk.Now())
+
+	timer := clk.NewTimer(time.Second)
+	require.Equal(t, 1, clk.Waiters())
+
+	clk.Advance(500 * time.Millisecond)
+	select {
+	case <-timer.C():
+		require.Fail(t, "timer fired early")
+	default:
+	}
+
+	clk.Advance(500 * time.Millisecond)
+	require.Equal(t, start.Add(time.Second), <-timer.C())
+	require.Equal(t, 0, clk.Waiters())
+	require.False(t, timer.Stop())
+
+	require.False(t, timer.Reset(time.Second))
+	require.True(t, timer.Stop())
+	clk.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		require.Fail(t, "stopped timer fired")
+	default:
+	}
+}
+
+func TestManual_ImmediateTimer(t *testing.T) {
+	clk := NewManual(time.Now())
+	timer := clk.NewTimer(0)
+	require.Equal(t, 0, clk.Waiters())
+	<-timer.C()
+}
+
+func TestSleep(t *testing.T) {
+	clk := NewManual(time.Now())
+	done := make(chan error)
+	go func() {
+		done <- Sleep(context.Background(), clk, time.Minute)
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(time.Minute)
+	require.NoError(t, <-done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		done <- Sleep(ctx, clk, time.Minute)
+	}()
+	clk.BlockUntil(1)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.NoError(t, Sleep(context.Background(), Real(), 0))
+}
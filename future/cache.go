@@ -0,0 +1,198 @@
+package future
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/casualjim/hie/clock"
+)
+
+// CacheOptions configures how long a Cache keeps results and how many it keeps
+type CacheOptions struct {
+	// TTL is how long a value is kept, 0 means values don't expire
+	TTL time.Duration
+	// ErrorTTL is how long an error is kept, 0 means errors aren't cached
+	ErrorTTL time.Duration
+	// MaxEntries limits the number of keys, the least recently used result is evicted first. 0 means unlimited
+	MaxEntries int
+	// Clock is used to expire results, nil means the wall clock
+	Clock clock.Clock
+}
+
+// NewCache creates a cache of futures
+func NewCache[K comparable, T any](opts CacheOptions) *Cache[K, T] {
+	return &Cache[K, T]{
+		opts:    opts,
+		clock:   clock.Or(opts.Clock),
+		entries: make(map[K]*cacheEntry[K, T]),
+		lru:     list.New(),
+	}
+}
+
+// Cache deduplicates concurrent computations of the same key and memoizes their results.
+type Cache[K comparable, T any] struct {
+	opts  CacheOptions
+	clock clock.Clock
+
+	mu      sync.Mutex
+	entries map[K]*cacheEntry[K, T]
+	lru     *list.List
+}
+
+type cacheEntry[K comparable, T any] struct {
+	key       K
+	shared    Future[T]
+	waiters   int
+	completed bool
+	value     T
+	err       error
+	expires   time.Time
+	elem      *list.Element
+}
+
+func (e *cacheEntry[K, T]) expired(now time.Time) bool {
+	return e.completed && !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// Do returns a future for the value of the key.
+// A cached result is returned right away, when the key is being computed the caller joins that computation,
+// otherwise the function is executed in a go routine.
+// The computation doesn't use the context of the caller, it is only cancelled when every caller waiting on it
+// has cancelled its future or seen its context end.
+func (c *Cache[K, T]) Do(ctx context.Context, key K, fn func(context.Context) (T, context.Context, error)) Future[T] {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && e.expired(c.clock.Now()) {
+		c.remove(e)
+		ok = false
+	}
+
+	if ok && e.completed {
+		c.lru.MoveToFront(e.elem)
+		value, err := e.value, e.err
+		c.mu.Unlock()
+
+		p := NewPromiseWithContext[T](ctx)
+		if err != nil {
+			p.Reject(err)
+		} else {
+			p.Resolve(value)
+		}
+		return p.Future()
+	}
+
+	if ok {
+		c.lru.MoveToFront(e.elem)
+		e.waiters++
+		c.mu.Unlock()
+		return c.join(ctx, e)
+	}
+
+	e = &cacheEntry[K, T]{key: key, waiters: 1}
+	e.elem = c.lru.PushFront(e)
+	e.shared = Do(fn)
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	// registered without holding the lock, because the callback runs right away when the computation already completed
+	e.shared.OnComplete(func(v T, err error) { c.settle(e, v, err) })
+	return c.join(ctx, e)
+}
+
+// join creates the future of a caller waiting on a shared computation
+func (c *Cache[K, T]) join(ctx context.Context, e *cacheEntry[K, T]) Future[T] {
+	p := NewPromiseWithContext[T](ctx)
+	e.shared.OnComplete(func(v T, err error) {
+		if err != nil {
+			p.Reject(err)
+			return
+		}
+		p.Resolve(v)
+	})
+
+	go func() {
+		select {
+		case <-p.Context().Done():
+			c.leave(e)
+		case <-e.shared.Done():
+		}
+	}()
+	return p.Future()
+}
+
+// leave cancels the shared computation when the last caller waiting on it is gone
+func (c *Cache[K, T]) leave(e *cacheEntry[K, T]) {
+	c.mu.Lock()
+	e.waiters--
+	abandoned := e.waiters == 0 && !e.completed
+	if abandoned && c.entries[e.key] == e {
+		c.remove(e)
+	}
+	c.mu.Unlock()
+
+	if abandoned {
+		e.shared.Cancel()
+	}
+}
+
+func (c *Cache[K, T]) settle(e *cacheEntry[K, T], value T, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[e.key] != e { // invalidated or abandoned
+		return
+	}
+	e.completed = true
+	e.value, e.err = value, err
+
+	switch {
+	case err != nil && c.opts.ErrorTTL <= 0:
+		c.remove(e)
+		return
+	case err != nil:
+		e.expires = c.clock.Now().Add(c.opts.ErrorTTL)
+	case c.opts.TTL > 0:
+		e.expires = c.clock.Now().Add(c.opts.TTL)
+	}
+	c.evict()
+}
+
+// evict removes the least recently used results while there are too many keys
+func (c *Cache[K, T]) evict() {
+	if c.opts.MaxEntries <= 0 {
+		return
+	}
+	for elem := c.lru.Back(); elem != nil && len(c.entries) > c.opts.MaxEntries; {
+		prev := elem.Prev()
+		if e := elem.Value.(*cacheEntry[K, T]); e.completed {
+			c.remove(e)
+		}
+		elem = prev
+	}
+}
+
+func (c *Cache[K, T]) remove(e *cacheEntry[K, T]) {
+	delete(c.entries, e.key)
+	c.lru.Remove(e.elem)
+}
+
+// Invalidate forgets the results of the keys.
+// Callers already waiting on a computation still receive its result, but it isn't cached.
+func (c *Cache[K, T]) Invalidate(keys ...K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if e, ok := c.entries[key]; ok {
+			c.remove(e)
+		}
+	}
+}
+
+// Len returns the number of keys that are cached or being computed
+func (c *Cache[K, T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,153 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/casualjim/hie/clock"
+	"github.com/casualjim/hie/future"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_Deduplicates(t *testing.T) {
+	t.Parallel()
+
+	cache := future.NewCache[string, int](future.CacheOptions{})
+	var count int64
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, context.Context, error) {
+		atomic.AddInt64(&count, 1)
+		<-release
+		return 42, ctx, nil
+	}
+
+	futures := make([]future.Future[int], 10)
+	for i := range futures {
+		futures[i] = cache.Do(context.Background(), "answer", fn)
+	}
+	close(release)
+	for _, f := range futures {
+		v, _, err := f.Get()
+		require.NoError(t, err)
+		assert.Equal(t, 42, v)
+	}
+
+	v, _, err := cache.Do(context.Background(), "answer", fn).Get()
+	require.NoError(t, err)
+	assert.Equal(t, 42, v)
+	assert.EqualValues(t, 1, atomic.LoadInt64(&count))
+}
+
+func TestCache_TTL(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	cache := future.NewCache[string, int](future.CacheOptions{TTL: time.Minute, Clock: clk})
+	var count int64
+
+	get := func() int {
+		v, _, err := cache.Do(context.Background(), "k", counted(&count, 1)).Get()
+		require.NoError(t, err)
+		return v
+	}
+
+	get()
+	get()
+	assert.EqualValues(t, 1, atomic.LoadInt64(&count))
+	clk.Advance(time.Minute)
+	get()
+	assert.EqualValues(t, 2, atomic.LoadInt64(&count))
+}
+
+func TestCache_Errors(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	exp := errors.New("expected")
+	var count int64
+	fail := func(ctx context.Context) (int, context.Context, error) {
+		atomic.AddInt64(&count, 1)
+		return 0, ctx, exp
+	}
+
+	uncached := future.NewCache[string, int](future.CacheOptions{Clock: clk})
+	for i := 0; i < 2; i++ {
+		_, _, err := uncached.Do(context.Background(), "k", fail).Get()
+		assert.Equal(t, exp, err)
+	}
+	assert.EqualValues(t, 2, atomic.LoadInt64(&count))
+
+	cached := future.NewCache[string, int](future.CacheOptions{ErrorTTL: time.Second, Clock: clk})
+	for i := 0; i < 2; i++ {
+		_, _, err := cached.Do(context.Background(), "k", fail).Get()
+		assert.Equal(t, exp, err)
+	}
+	assert.EqualValues(t, 3, atomic.LoadInt64(&count))
+	clk.Advance(time.Second)
+	cached.Do(context.Background(), "k", fail).Get()
+	assert.EqualValues(t, 4, atomic.LoadInt64(&count))
+}
+
+func TestCache_MaxEntries(t *testing.T) {
+	t.Parallel()
+
+	cache := future.NewCache[int, int](future.CacheOptions{MaxEntries: 2})
+	var count int64
+	for _, k := range []int{1, 2, 1, 3} {
+		cache.Do(context.Background(), k, counted(&count, k)).Get()
+	}
+	assert.Equal(t, 2, cache.Len())
+	assert.EqualValues(t, 3, atomic.LoadInt64(&count))
+
+	cache.Do(context.Background(), 1, counted(&count, 1)).Get() // 1 was used recently and is still cached
+	assert.EqualValues(t, 3, atomic.LoadInt64(&count))
+	cache.Do(context.Background(), 2, counted(&count, 2)).Get() // 2 was evicted
+	assert.EqualValues(t, 4, atomic.LoadInt64(&count))
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	cache := future.NewCache[string, int](future.CacheOptions{})
+	var count int64
+	cache.Do(context.Background(), "k", counted(&count, 1)).Get()
+	cache.Invalidate("k")
+	assert.Equal(t, 0, cache.Len())
+	cache.Do(context.Background(), "k", counted(&count, 1)).Get()
+	assert.EqualValues(t, 2, atomic.LoadInt64(&count))
+}
+
+func TestCache_WaiterCancellation(t *testing.T) {
+	t.Parallel()
+
+	cache := future.NewCache[string, int](future.CacheOptions{})
+	started := make(chan struct{}, 1)
+	shared := make(chan context.Context, 1)
+	fn := func(ctx context.Context) (int, context.Context, error) {
+		shared <- ctx
+		started <- struct{}{}
+		<-ctx.Done()
+		return 0, ctx, ctx.Err()
+	}
+
+	first := cache.Do(context.Background(), "k", fn)
+	<-started
+	ctx, cancel := context.WithCancel(context.Background())
+	second := cache.Do(ctx, "k", fn)
+	sharedCtx := <-shared
+
+	first.Cancel()
+	_, _, err := first.Get()
+	assert.Equal(t, context.Canceled, err)
+	assert.NoError(t, sharedCtx.Err(), "another caller is still waiting")
+
+	cancel()
+	_, _, err = second.Get()
+	assert.Equal(t, context.Canceled, err)
+	<-sharedCtx.Done()
+	assert.Eventually(t, func() bool { return cache.Len() == 0 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,124 @@
+package future_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/casualjim/hie/future"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callbackErrors captures the errors reported by callbacks, tests using it can't run in parallel
+func callbackErrors(t *testing.T) <-chan error {
+	errs := make(chan error, 10)
+	future.SetCallbackErrorHandler(func(err error) { errs <- err })
+	t.Cleanup(func() { future.SetCallbackErrorHandler(nil) })
+	return errs
+}
+
+func TestOnComplete(t *testing.T) {
+	t.Parallel()
+
+	p := future.NewPromise[int]()
+	f := p.Future()
+
+	values := make(chan int, 3)
+	f.OnSuccess(func(v int) { values <- v })
+	f.OnComplete(func(v int, err error) {
+		assert.NoError(t, err)
+		values <- v * 10
+	})
+	f.OnFailure(func(error) { values <- -1 })
+
+	p.Resolve(2)
+	got := []int{<-values, <-values}
+	assert.ElementsMatch(t, []int{2, 20}, got)
+
+	// registering after completion runs right away
+	f.OnSuccess(func(v int) { values <- v * 100 })
+	assert.Equal(t, 200, <-values)
+	assert.Empty(t, values)
+}
+
+func TestOnFailure(t *testing.T) {
+	t.Parallel()
+
+	exp := errors.New("expected")
+	errs := make(chan error, 1)
+	f := failure(exp)
+	f.OnSuccess(func(int) { errs <- errors.New("unexpected success") })
+	f.OnFailure(func(err error) { errs <- err })
+	assert.Equal(t, exp, <-errs)
+}
+
+func TestOnComplete_ExactlyOnce(t *testing.T) {
+	t.Parallel()
+
+	p := future.NewPromise[int]()
+	f := p.Future()
+
+	var mu sync.Mutex
+	var count int
+	var wg sync.WaitGroup
+	wg.Add(50)
+	for i := 0; i < 50; i++ {
+		f.OnComplete(func(int, error) {
+			mu.Lock()
+			count++
+			mu.Unlock()
+			wg.Done()
+		})
+	}
+	go p.Resolve(1)
+	for i := 0; i < 5; i++ {
+		go f.Get()
+	}
+	wg.Wait()
+	f.Get()
+	mu.Lock()
+	assert.Equal(t, 50, count)
+	mu.Unlock()
+}
+
+func TestOnComplete_Executor(t *testing.T) {
+	t.Parallel()
+
+	var executed int
+	exec := future.ExecutorFunc(func(task func()) error {
+		executed++
+		task()
+		return nil
+	})
+
+	values := make(chan int, 1)
+	v := value(4)
+	v.Get()
+	v.OnSuccess(func(i int) { values <- i }, exec)
+	assert.Equal(t, 4, <-values)
+	assert.Equal(t, 1, executed)
+}
+
+func TestOnComplete_Panic(t *testing.T) {
+	errs := callbackErrors(t)
+
+	v := value(1)
+	v.Get()
+	v.OnSuccess(func(int) { panic("boom") })
+
+	err := <-errs
+	var pe *future.PanicError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "boom", pe.Value)
+	assert.NotEmpty(t, pe.Stack)
+}
+
+func TestOnComplete_Rejected(t *testing.T) {
+	errs := callbackErrors(t)
+
+	exp := errors.New("rejected")
+	v := value(1)
+	v.OnSuccess(func(int) {}, future.ExecutorFunc(func(func()) error { return exp }))
+	assert.Equal(t, exp, <-errs)
+}
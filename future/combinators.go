@@ -0,0 +1,140 @@
+package future
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/multierr"
+)
+
+// ErrNoFutures is returned by combinators that need at least one future to produce a result
+var ErrNoFutures = errors.New("no futures provided")
+
+// Settled holds the outcome of a future
+type Settled[T any] struct {
+	Value T
+	Err   error
+}
+
+type settledAt[T any] struct {
+	Settled[T]
+	Ctx   context.Context
+	Index int
+}
+
+// settle publishes the outcomes of the futures in completion order, lazy futures are started
+func settle[T any](futures []Future[T]) <-chan settledAt[T] {
+	c := make(chan settledAt[T], len(futures))
+	for i, f := range futures {
+		i, f := i, f
+		f.OnComplete(func(T, error) {
+			v, ctx, err := f.Get()
+			c <- settledAt[T]{Settled: Settled[T]{Value: v, Err: err}, Ctx: ctx, Index: i}
+		})
+		demand(f)
+	}
+	return c
+}
+
+// cancelAll cancels the futures, except the one at the skipped index whose context may still be in use
+func cancelAll[T any](futures []Future[T], skip ...int) {
+	for i, f := range futures {
+		if len(skip) > 0 && skip[0] == i {
+			continue
+		}
+		f.Cancel()
+	}
+}
+
+// All creates a future that succeeds with the values of all the futures in the order they were provided.
+// It fails as soon as one of the futures fails, the remaining futures are cancelled in that case.
+// Cancelling the returned future cancels all the futures.
+func All[T any](futures ...Future[T]) Future[[]T] {
+	return DoWithContext(context.Background(), func(ctx context.Context) ([]T, context.Context, error) {
+		values := make([]T, len(futures))
+		c := settle(futures)
+		for range futures {
+			select {
+			case <-ctx.Done():
+				cancelAll(futures)
+				return nil, ctx, ctx.Err()
+			case r := <-c:
+				if r.Err != nil {
+					cancelAll(futures, r.Index)
+					return nil, ctx, r.Err
+				}
+				values[r.Index] = r.Value
+			}
+		}
+		return values, ctx, nil
+	})
+}
+
+// AllSettled creates a future that waits for all the futures to complete and succeeds with their outcomes
+// in the order they were provided.
+// Cancelling the returned future cancels all the futures.
+func AllSettled[T any](futures ...Future[T]) Future[[]Settled[T]] {
+	return DoWithContext(context.Background(), func(ctx context.Context) ([]Settled[T], context.Context, error) {
+		outcomes := make([]Settled[T], len(futures))
+		c := settle(futures)
+		for range futures {
+			select {
+			case <-ctx.Done():
+				cancelAll(futures)
+				return nil, ctx, ctx.Err()
+			case r := <-c:
+				outcomes[r.Index] = r.Settled
+			}
+		}
+		return outcomes, ctx, nil
+	})
+}
+
+// Any creates a future that succeeds with the value of the first future that succeeds, the remaining futures are cancelled.
+// When all the futures fail, it fails with the combination of their errors.
+// Cancelling the returned future cancels all the futures.
+func Any[T any](futures ...Future[T]) Future[T] {
+	return DoWithContext(context.Background(), func(ctx context.Context) (T, context.Context, error) {
+		var zero T
+		if len(futures) == 0 {
+			return zero, ctx, ErrNoFutures
+		}
+
+		errs := make([]error, len(futures))
+		c := settle(futures)
+		for range futures {
+			select {
+			case <-ctx.Done():
+				cancelAll(futures)
+				return zero, ctx, ctx.Err()
+			case r := <-c:
+				if r.Err == nil {
+					cancelAll(futures, r.Index)
+					return r.Value, r.Ctx, nil
+				}
+				errs[r.Index] = r.Err
+			}
+		}
+		return zero, ctx, multierr.Combine(errs...)
+	})
+}
+
+// Race creates a future that completes with the outcome of the first future that completes, the remaining futures are cancelled.
+// Cancelling the returned future cancels all the futures.
+func Race[T any](futures ...Future[T]) Future[T] {
+	return DoWithContext(context.Background(), func(ctx context.Context) (T, context.Context, error) {
+		var zero T
+		if len(futures) == 0 {
+			return zero, ctx, ErrNoFutures
+		}
+
+		select {
+		case <-ctx.Done():
+			cancelAll(futures)
+			return zero, ctx, ctx.Err()
+		case r := <-settle(futures):
+			cancelAll(futures, r.Index)
+			return r.Value, r.Ctx, r.Err
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/casualjim/hie/future"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func blocking(started chan<- struct{}) future.Future[int] {
+	return future.Do(func(ctx context.Context) (int, context.Context, error) {
+		if started != nil {
+			started <- struct{}{}
+		}
+		<-ctx.Done()
+		return 0, ctx, ctx.Err()
+	})
+}
+
+func value(v int) future.Future[int] {
+	return future.Do(future.Func(func() (int, error) { return v, nil }))
+}
+
+func failure(err error) future.Future[int] {
+	return future.Do(future.Func(func() (int, error) { return 0, err }))
+}
+
+func waitOn(releases <-chan struct{}, v int, err error) future.Future[int] {
+	return future.Do(func(ctx context.Context) (int, context.Context, error) {
+		select {
+		case <-releases:
+			return v, ctx, err
+		case <-ctx.Done():
+			return 0, ctx, ctx.Err()
+		}
+	})
+}
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	values, _, err := future.All(value(1), value(2), value(3)).Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, values)
+
+	values, _, err = future.All[int]().Get()
+	require.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func TestAll_FailFast(t *testing.T) {
+	t.Parallel()
+
+	exp := errors.New("expected")
+	slow := blocking(nil)
+	_, _, err := future.All(slow, failure(exp)).Get()
+	assert.Equal(t, exp, err)
+
+	_, _, err = slow.Get()
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestAll_Cancel(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 1)
+	slow := blocking(started)
+	all := future.All(slow, value(1))
+	<-started
+	all.Cancel()
+
+	_, _, err := all.Get()
+	assert.Equal(t, context.Canceled, err)
+	_, _, err = slow.Get()
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestAllSettled(t *testing.T) {
+	t.Parallel()
+
+	exp := errors.New("expected")
+	outcomes, _, err := future.AllSettled(value(1), failure(exp), value(3)).Get()
+	require.NoError(t, err)
+	assert.Equal(t, []future.Settled[int]{{Value: 1}, {Err: exp}, {Value: 3}}, outcomes)
+}
+
+func TestAny(t *testing.T) {
+	t.Parallel()
+
+	slow := blocking(nil)
+	v, ctx, err := future.Any(failure(errors.New("first")), slow, value(2)).Get()
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+	assert.NoError(t, ctx.Err())
+
+	_, _, err = slow.Get()
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestAny_AllFail(t *testing.T) {
+	t.Parallel()
+
+	first, second := errors.New("first"), errors.New("second")
+	_, _, err := future.Any(failure(first), failure(second)).Get()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, first)
+	assert.ErrorIs(t, err, second)
+
+	_, _, err = future.Any[int]().Get()
+	assert.Equal(t, future.ErrNoFutures, err)
+}
+
+func TestRace(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	exp := errors.New("expected")
+	slow := blocking(nil)
+	winner := waitOn(release, 0, exp)
+	race := future.Race(slow, winner)
+	close(release)
+
+	_, _, err := race.Get()
+	assert.Equal(t, exp, err)
+	_, _, err = slow.Get()
+	assert.Equal(t, context.Canceled, err)
+
+	_, _, err = future.Race[int]().Get()
+	assert.Equal(t, future.ErrNoFutures, err)
+}
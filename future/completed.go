@@ -0,0 +1,82 @@
+package future
+
+import (
+	"context"
+
+	"github.com/casualjim/hie"
+)
+
+// Completed is the outcome of a future together with its position in the list of futures
+type Completed[T any] struct {
+	Settled[T]
+	Index int
+}
+
+// AsCompleted returns an iterator that yields the outcomes of the futures in the order in which they complete.
+// The iterator ends when all the futures have completed or when the context is done,
+// closing the iterator or ending it through the context cancels the futures that haven't been yielded yet.
+func AsCompleted[T any](ctx context.Context, futures ...Future[T]) hie.Iter[Completed[T]] {
+	return &completedIter[T]{
+		ctx:     ctx,
+		futures: futures,
+		yielded: make([]bool, len(futures)),
+		c:       settle(futures),
+	}
+}
+
+type completedIter[T any] struct {
+	ctx     context.Context
+	futures []Future[T]
+	yielded []bool
+	count   int
+	c       <-chan settledAt[T]
+	pending *Completed[T]
+	closed  bool
+}
+
+func (c *completedIter[T]) HasNext() bool {
+	if c.pending != nil {
+		return true
+	}
+	if c.closed || c.count == len(c.futures) {
+		return false
+	}
+
+	select {
+	case <-c.ctx.Done():
+		_ = c.Close()
+		return false
+	case r := <-c.c:
+		c.count++
+		c.yielded[r.Index] = true
+		c.pending = &Completed[T]{Settled: r.Settled, Index: r.Index}
+		return true
+	}
+}
+
+func (c *completedIter[T]) Next() Completed[T] {
+	if c.closed {
+		panic("next called on a closed iterator")
+	}
+	if !c.HasNext() {
+		panic("iterating beyond end")
+	}
+	res := c.pending
+	c.pending = nil
+	return *res
+}
+
+// Close cancels the futures that haven't been yielded yet
+func (c *completedIter[T]) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.pending = nil
+	for i, f := range c.futures {
+		if !c.yielded[i] {
+			f.Cancel()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/casualjim/hie/future"
+	"github.com/casualjim/hie/iter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsCompleted(t *testing.T) {
+	t.Parallel()
+
+	exp := errors.New("expected")
+	first, second, third := future.NewPromise[int](), future.NewPromise[int](), future.NewPromise[int]()
+	it := future.AsCompleted(context.Background(), first.Future(), second.Future(), third.Future())
+
+	second.Resolve(2)
+	require.True(t, it.HasNext())
+	assert.Equal(t, future.Completed[int]{Settled: future.Settled[int]{Value: 2}, Index: 1}, it.Next())
+
+	third.Reject(exp)
+	require.True(t, it.HasNext())
+	assert.Equal(t, future.Completed[int]{Settled: future.Settled[int]{Err: exp}, Index: 2}, it.Next())
+
+	first.Resolve(1)
+	require.True(t, it.HasNext())
+	assert.Equal(t, 0, it.Next().Index)
+
+	assert.False(t, it.HasNext())
+	assert.Panics(t, func() { it.Next() })
+}
+
+func TestAsCompleted_Combinators(t *testing.T) {
+	t.Parallel()
+
+	exp := errors.New("expected")
+	it := future.AsCompleted(context.Background(), value(1), failure(exp), value(3))
+	values := iter.FilterMap(it, func(c future.Completed[int]) (int, bool) {
+		return c.Value, c.Err == nil
+	})
+	assert.ElementsMatch(t, []int{1, 3}, iter.Collect(values))
+}
+
+func TestAsCompleted_Close(t *testing.T) {
+	t.Parallel()
+
+	done := value(1)
+	slow := blocking(nil)
+	it := future.AsCompleted(context.Background(), done, slow)
+
+	require.True(t, it.HasNext())
+	assert.Equal(t, 0, it.Next().Index)
+	require.NoError(t, iter.Close(it))
+	assert.False(t, it.HasNext())
+
+	_, _, err := slow.Get()
+	assert.Equal(t, context.Canceled, err)
+	_, _, err = done.Get()
+	assert.NoError(t, err)
+}
+
+func TestAsCompleted_Context(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	slow := blocking(nil)
+	it := future.AsCompleted(ctx, slow)
+	cancel()
+
+	assert.False(t, it.HasNext())
+	_, _, err := slow.Get()
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,206 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrExecutorShutdown is returned by an executor that rejects a task because it is shut down
+	ErrExecutorShutdown = errors.New("executor is shut down")
+	// ErrQueueFull is returned by an executor that rejects a task because its queue is full
+	ErrQueueFull = errors.New("executor queue is full")
+)
+
+// Executor runs tasks, usually on another go routine.
+// An executor returns an error when it rejects a task.
+type Executor interface {
+	Execute(task func()) error
+}
+
+// ExecutorFunc adapts a function to the Executor interface
+type ExecutorFunc func(task func()) error
+
+func (e ExecutorFunc) Execute(task func()) error { return e(task) }
+
+// ExecutorService is an executor that can be shut down
+type ExecutorService interface {
+	Executor
+	// Shutdown stops accepting tasks and waits until the accepted tasks are done or the context is done
+	Shutdown(ctx context.Context) error
+}
+
+// goExecutor runs every task in a new go routine, it's the default executor of futures
+var goExecutor = ExecutorFunc(func(task func()) error {
+	go task()
+	return nil
+})
+
+// tracker keeps count of the running tasks of an executor so that it can shut down gracefully
+type tracker struct {
+	mu       sync.Mutex
+	shutdown bool
+	running  sync.WaitGroup
+}
+
+func (t *tracker) enter() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.shutdown {
+		return ErrExecutorShutdown
+	}
+	t.running.Add(1)
+	return nil
+}
+
+func (t *tracker) leave() {
+	t.running.Done()
+}
+
+func (t *tracker) stop() {
+	t.mu.Lock()
+	t.shutdown = true
+	t.mu.Unlock()
+}
+
+func (t *tracker) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NewUnboundedExecutor creates an executor that runs every task in a new go routine
+func NewUnboundedExecutor() *UnboundedExecutor {
+	return &UnboundedExecutor{}
+}
+
+// UnboundedExecutor runs every task in a new go routine
+type UnboundedExecutor struct {
+	tasks tracker
+}
+
+func (u *UnboundedExecutor) Execute(task func()) error {
+	if err := u.tasks.enter(); err != nil {
+		return err
+	}
+	go func() {
+		defer u.tasks.leave()
+		task()
+	}()
+	return nil
+}
+
+func (u *UnboundedExecutor) Shutdown(ctx context.Context) error {
+	u.tasks.stop()
+	return u.tasks.wait(ctx)
+}
+
+// NewCallerRunsExecutor creates an executor that runs every task on the go routine that submits it
+func NewCallerRunsExecutor() *CallerRunsExecutor {
+	return &CallerRunsExecutor{}
+}
+
+// CallerRunsExecutor runs every task on the go routine that submits it
+type CallerRunsExecutor struct {
+	tasks tracker
+}
+
+func (c *CallerRunsExecutor) Execute(task func()) error {
+	if err := c.tasks.enter(); err != nil {
+		return err
+	}
+	defer c.tasks.leave()
+	task()
+	return nil
+}
+
+func (c *CallerRunsExecutor) Shutdown(ctx context.Context) error {
+	c.tasks.stop()
+	return c.tasks.wait(ctx)
+}
+
+// NewFixedPoolExecutor creates an executor that runs tasks on a fixed number of go routines.
+// Tasks wait in a queue of queueSize until a worker is available, when the queue is full tasks are rejected with ErrQueueFull.
+// A rejected continuation runs in the go routine that completed the future it continues instead of failing.
+// A continuation that blocks on another task of the same pool, like the continuation of FlatMap, deadlocks when every worker is busy.
+func NewFixedPoolExecutor(workers, queueSize int) *FixedPoolExecutor {
+	if workers < 1 {
+		panic("a fixed pool needs at least 1 worker")
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	p := &FixedPoolExecutor{
+		queue: make(chan func(), queueSize),
+	}
+	p.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// FixedPoolExecutor runs tasks on a fixed number of go routines
+type FixedPoolExecutor struct {
+	mu       sync.RWMutex
+	shutdown bool
+	queue    chan func()
+	workers  sync.WaitGroup
+}
+
+func (p *FixedPoolExecutor) work() {
+	defer p.workers.Done()
+	for task := range p.queue {
+		task()
+	}
+}
+
+func (p *FixedPoolExecutor) Execute(task func()) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.shutdown {
+		return ErrExecutorShutdown
+	}
+	select {
+	case p.queue <- task:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// QueueLen returns the number of tasks that are waiting for a worker
+func (p *FixedPoolExecutor) QueueLen() int {
+	return len(p.queue)
+}
+
+// Shutdown stops accepting tasks and waits until the queued and running tasks are done or the context is done
+func (p *FixedPoolExecutor) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.shutdown {
+		p.shutdown = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,279 @@
+package future_test
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/casualjim/hie/future"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedPoolExecutor(t *testing.T) {
+	t.Parallel()
+
+	pool := future.NewFixedPoolExecutor(2, 100)
+	var running, peak int64
+	work := func(ctx context.Context) (int, context.Context, error) {
+		n := atomic.AddInt64(&running, 1)
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+		runtime.Gosched()
+		atomic.AddInt64(&running, -1)
+		return 1, ctx, nil
+	}
+
+	futures := make([]future.Future[int], 20)
+	for i := range futures {
+		futures[i] = future.DoWithExecutor(context.Background(), pool, work).
+			AndThen(future.ThenFunc(func(i int) (int, error) { return i + 1, nil }))
+	}
+	for _, f := range futures {
+		v, _, err := f.Get()
+		require.NoError(t, err)
+		assert.Equal(t, 2, v)
+	}
+	assert.LessOrEqual(t, atomic.LoadInt64(&peak), int64(2))
+
+	require.NoError(t, pool.Shutdown(context.Background()))
+	_, _, err := future.DoWithExecutor(context.Background(), pool, work).Get()
+	assert.Equal(t, future.ErrExecutorShutdown, err)
+}
+
+func TestFixedPoolExecutor_QueueFull(t *testing.T) {
+	t.Parallel()
+
+	pool := future.NewFixedPoolExecutor(1, 1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	blocker := func(ctx context.Context) (int, context.Context, error) {
+		started <- struct{}{}
+		<-release
+		return 1, ctx, nil
+	}
+
+	first := future.DoWithExecutor(context.Background(), pool, blocker)
+	<-started
+	queued := future.DoWithExecutor(context.Background(), pool, future.Func(func() (int, error) { return 2, nil }))
+	assert.Equal(t, 1, pool.QueueLen())
+
+	_, _, err := future.DoWithExecutor(context.Background(), pool, blocker).Get()
+	assert.Equal(t, future.ErrQueueFull, err)
+
+	close(release)
+	v, _, err := first.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	v, _, err = queued.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestFixedPoolExecutor_RejectedContinuation(t *testing.T) {
+	t.Parallel()
+
+	pool := future.NewFixedPoolExecutor(1, 0)
+	release := make(chan struct{})
+	var f future.Future[int]
+	require.Eventually(t, func() bool {
+		// a task is only accepted while the worker waits for one
+		f = future.DoWithExecutor(context.Background(), pool, func(ctx context.Context) (int, context.Context, error) {
+			<-release
+			return 1, ctx, nil
+		})
+		return f.State() == future.Pending
+	}, time.Second, time.Millisecond)
+
+	next := f.AndThen(future.ThenFunc(func(i int) (int, error) { return i + 1, nil })).
+		AndThen(future.ThenFunc(func(i int) (int, error) { return i + 1, nil }))
+	close(release)
+	v, _, err := next.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+}
+
+func TestFixedPoolExecutor_FlatMapOnOtherExecutor(t *testing.T) {
+	t.Parallel()
+
+	pool := future.NewFixedPoolExecutor(1, 1)
+	f := future.DoWithExecutor(context.Background(), pool, future.Func(func() (int, error) { return 1, nil }))
+	next := future.FlatMap(f, func(ctx context.Context, i int) future.Future[int] {
+		return future.DoWithExecutor(ctx, pool, future.Func(func() (int, error) { return i + 1, nil }))
+	}, future.NewUnboundedExecutor())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	v, _, err := next.GetContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestFixedPoolExecutor_ShutdownWaits(t *testing.T) {
+	t.Parallel()
+
+	pool := future.NewFixedPoolExecutor(1, 1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var done int64
+	require.NoError(t, pool.Execute(func() {
+		close(started)
+		<-release
+		atomic.AddInt64(&done, 1)
+	}))
+	<-started
+	require.NoError(t, pool.Execute(func() { atomic.AddInt64(&done, 1) }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, pool.Shutdown(ctx))
+	assert.Equal(t, future.ErrExecutorShutdown, pool.Execute(func() {}))
+
+	close(release)
+	require.NoError(t, pool.Shutdown(context.Background()))
+	assert.EqualValues(t, 2, atomic.LoadInt64(&done))
+}
+
+func TestCallerRunsExecutor(t *testing.T) {
+	t.Parallel()
+
+	exec := future.NewCallerRunsExecutor()
+	var ran bool
+	f := future.DoWithExecutor(context.Background(), exec, future.Func(func() (int, error) {
+		ran = true
+		return 3, nil
+	}))
+	assert.True(t, ran, "the function runs before the future is returned")
+	assert.True(t, f.IsDone())
+
+	g := f.AndThen(future.ThenFunc(func(i int) (int, error) { return i * 2, nil }))
+	assert.True(t, g.IsDone(), "continuations inherit the executor")
+	v, _, err := g.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 6, v)
+
+	require.NoError(t, exec.Shutdown(context.Background()))
+	assert.Equal(t, future.ErrExecutorShutdown, exec.Execute(func() {}))
+}
+
+func TestUnboundedExecutor(t *testing.T) {
+	t.Parallel()
+
+	exec := future.NewUnboundedExecutor()
+	release := make(chan struct{})
+	f := future.DoWithExecutor(context.Background(), exec, func(ctx context.Context) (int, context.Context, error) {
+		<-release
+		return 1, ctx, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, exec.Shutdown(ctx))
+
+	close(release)
+	require.NoError(t, exec.Shutdown(context.Background()))
+	v, _, err := f.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, future.ErrExecutorShutdown, exec.Execute(func() {}))
+}
+
+func TestAndThen_OnExecutor(t *testing.T) {
+	t.Parallel()
+
+	var count int64
+	exec := future.ExecutorFunc(func(task func()) error {
+		atomic.AddInt64(&count, 1)
+		go task()
+		return nil
+	})
+
+	v, _, err := value(1).
+		AndThen(future.ThenFunc(func(i int) (int, error) { return i + 1, nil }), exec).
+		OrElse(future.ElseFunc(func(error) (int, error) { return 0, nil })).
+		Get()
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+	assert.EqualValues(t, 2, atomic.LoadInt64(&count), "OrElse inherits the executor of AndThen")
+}
+
+// not parallel, it counts the go routines of the process
+func TestFixedPoolExecutor_NoWatcherPerFuture(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool := future.NewFixedPoolExecutor(2, 1000)
+	gate := make(chan struct{})
+	before := runtime.NumGoroutine()
+
+	futures := make([]future.Future[int], 100)
+	for i := range futures {
+		futures[i] = future.DoWithExecutor(ctx, pool, func(ctx context.Context) (int, context.Context, error) {
+			select {
+			case <-gate:
+			case <-ctx.Done():
+			}
+			return 1, ctx, nil
+		}).AndThen(future.ThenFunc(func(i int) (int, error) { return i + 1, nil }))
+		_ = futures[i].Done()
+	}
+	assert.Less(t, runtime.NumGoroutine()-before, 10)
+	assert.Equal(t, 1, watcherGoroutines()) // one watcher for all the futures of the context
+
+	cancel()
+	for _, f := range futures {
+		<-f.Done()
+		_, _, err := f.Get()
+		require.ErrorIs(t, err, context.Canceled)
+	}
+	close(gate)
+	require.NoError(t, pool.Shutdown(context.Background()))
+}
+
+// watcherGoroutines counts the go routines that wait for the cancellation of a context on behalf of futures
+func watcherGoroutines() int {
+	buf := make([]byte, 1<<20)
+	return strings.Count(string(buf[:runtime.Stack(buf, true)]), "created by github.com/casualjim/hie/future.watch")
+}
+
+// not parallel, it counts the go routines of the process
+func TestFutures_WatcherExitsWhenResolved(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := 0; i < 50; i++ {
+		f := future.DoWithContext(ctx, func(ctx context.Context) (int, context.Context, error) { return 1, ctx, nil })
+		_, _, err := f.AndThen(future.ThenFunc(func(i int) (int, error) { return i, nil })).Get()
+		require.NoError(t, err)
+	}
+	assert.Eventually(t, func() bool { return watcherGoroutines() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestFutures_NoWatcherForUnstartedLazy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	futures := make([]future.Future[int], 0, 50)
+	for i := 0; i < 50; i++ {
+		f := future.LazyWithContext(ctx, func(ctx context.Context) (int, context.Context, error) { return 1, ctx, nil })
+		futures = append(futures, f.AndThen(future.ThenFunc(func(i int) (int, error) { return i, nil })))
+	}
+	require.Equal(t, 0, watcherGoroutines())
+
+	futures[0].Cancel()
+	assert.Equal(t, future.Cancelled, futures[0].State())
+	assert.Equal(t, future.Pending, futures[1].State())
+	require.Equal(t, 0, watcherGoroutines())
+
+	v, _, err := futures[1].Get()
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+	assert.Eventually(t, func() bool { return watcherGoroutines() == 0 }, time.Second, time.Millisecond)
+}
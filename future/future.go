@@ -3,6 +3,9 @@ package future
 import (
 	"context"
 	"sync"
+
+	"github.com/casualjim/hie"
+	"github.com/casualjim/hie/opt"
 )
 
 type result[T any] struct {
@@ -16,9 +19,17 @@ type result[T any] struct {
 //
 // This is loosely based on this paper: http://www.home.hs-karlsruhe.de/~suma0002/publications/events-to-futures.pdf
 type Future[T any] interface {
-	AndThen(func(context.Context, T) (T, context.Context, error)) Future[T]
-	OrElse(func(context.Context, error) (T, context.Context, error)) Future[T]
+	AndThen(func(context.Context, T) (T, context.Context, error), ...Executor) Future[T]
+	OrElse(func(context.Context, error) (T, context.Context, error), ...Executor) Future[T]
 	Get() (T, context.Context, error)
+	GetContext(context.Context) (T, context.Context, error)
+	TryGet() (opt.Option[T], error)
+	Done() <-chan struct{}
+	IsDone() bool
+	State() State
+	OnSuccess(func(T), ...Executor)
+	OnFailure(func(error), ...Executor)
+	OnComplete(func(T, error), ...Executor)
 	Cancel()
 }
 
@@ -55,32 +66,125 @@ func Do[T any](fn func(context.Context) (T, context.Context, error)) Future[T] {
 // DoWithContext creates a future that executes the function in a go routine.
 // The context is passed into the function so that it can be used for handling cancellation
 // The function is expected to either pass the original context along or provide a new context based off the one passed in.
+// A panic in the function is recovered and returned as a *PanicError.
 func DoWithContext[T any](ctx context.Context, fn func(context.Context) (T, context.Context, error)) Future[T] {
-	inner, cancel := context.WithCancel(ctx)
-	c := make(chan result[T], 1)
-	go func() {
-		defer close(c)
-		v, ctx, e := fn(inner)
-		c <- result[T]{Value: v, Err: e, Ctx: ctx}
-	}()
-
-	return &future[T]{
-		C:      c,
-		cancel: cancel,
-		ctx:    inner,
+	return DoWithExecutor(ctx, nil, fn)
+}
+
+// DoWithExecutor creates a future that executes the function on the executor, a nil executor starts a go routine.
+// Continuations of the future run on the same executor unless another one is provided.
+// When the executor rejects the function the future fails with the error of the executor.
+func DoWithExecutor[T any](ctx context.Context, executor Executor, fn func(context.Context) (T, context.Context, error)) Future[T] {
+	f := newFuture[T](newScope(ctx), executor)
+	f.begin()
+	f.run(task(f, fn))
+	return f
+}
+
+// Lazy creates a future that executes the function in a go routine when the future is first waited on with Get, GetContext or Done.
+// Continuations of a lazy future are lazy too, waiting on a continuation starts the future it continues.
+// Inspecting the future with TryGet, IsDone or State, or registering callbacks doesn't start it.
+// Cancelling a lazy future before it started prevents the function from running.
+func Lazy[T any](fn func(context.Context) (T, context.Context, error)) Future[T] {
+	return LazyWithContext(context.Background(), fn)
+}
+
+// LazyWithContext creates a lazy future whose function receives a context derived from the provided one
+func LazyWithContext[T any](ctx context.Context, fn func(context.Context) (T, context.Context, error)) Future[T] {
+	f := newFuture[T](newScope(ctx), nil)
+	f.launch = func() {
+		if f.ctx.Err() == nil {
+			f.run(task(f, fn))
+		}
+	}
+	return f
+}
+
+func task[T any](f *future[T], fn func(context.Context) (T, context.Context, error)) func() result[T] {
+	return func() result[T] {
+		v, ctx, e := protect(f.ctx, func() (T, context.Context, error) { return fn(f.ctx) })
+		return result[T]{Value: v, Err: e, Ctx: ctx}
+	}
+}
+
+func newFuture[T any](s *scope, executor Executor) *future[T] {
+	if executor == nil {
+		executor = goExecutor
+	}
+	f := &future[T]{
+		C:      make(chan result[T], 1),
+		scope:  s,
+		cancel: s.Cancel,
+		ctx:    s.ctx,
+		exec:   executor,
 		once:   new(sync.Once),
+		start:  new(sync.Once),
+		done:   make(chan struct{}),
+		mu:     new(sync.Mutex),
 	}
+	return f
 }
 
 type future[T any] struct {
 	C      chan result[T]
+	scope  *scope
 	cancel context.CancelFunc
 	ctx    context.Context
+	exec   Executor
 	once   *sync.Once
 	val    *result[T]
+	start  *sync.Once
+	launch func() // starts a lazy future, nil when the future started right away
+	done   chan struct{}
+
+	mu        *sync.Mutex
+	callbacks []func()
 }
 
-func (f *future[T]) Get() (T, context.Context, error) {
+// begin joins the future to its scope and starts it when it is lazy.
+// A lazy future stays out of its scope until it is started, so that nothing waits on a future that may never run.
+func (f *future[T]) begin() {
+	f.start.Do(func() {
+		f.scope.join(f)
+		if f.launch != nil {
+			f.launch()
+		}
+	})
+}
+
+// demand starts the future when it is lazy
+func demand[T any](f Future[T]) {
+	if ff, ok := f.(*future[T]); ok {
+		ff.begin()
+	}
+}
+
+// run submits the task to the executor and completes the future with its result
+func (f *future[T]) run(task func() result[T]) {
+	if err := f.exec.Execute(func() { f.complete(task()) }); err != nil {
+		f.complete(result[T]{Err: err, Ctx: f.ctx})
+	}
+}
+
+// follow runs a continuation on the executor, when the executor rejects it the continuation runs in the calling go routine.
+// Failing the continuation instead would lose the outcome of the future it continues, for example when a bounded pool
+// is busy with the very task that completes that future.
+func (f *future[T]) follow(task func() result[T]) {
+	if err := f.exec.Execute(func() { f.complete(task()) }); err != nil {
+		f.complete(task())
+	}
+}
+
+// complete publishes the result and resolves the future right away, so that callbacks don't need a go routine to wait for it
+func (f *future[T]) complete(r result[T]) {
+	f.C <- r
+	close(f.C)
+	f.resolve()
+}
+
+// resolve waits for the outcome of the future and caches it, it's safe to call from several go routines
+func (f *future[T]) resolve() {
+	var callbacks []func()
 	f.once.Do(func() {
 		select {
 		case <-f.ctx.Done():
@@ -88,7 +192,38 @@ func (f *future[T]) Get() (T, context.Context, error) {
 		case val := <-f.C:
 			f.val = &val
 		}
+
+		f.mu.Lock()
+		close(f.done)
+		callbacks = f.callbacks
+		f.callbacks = nil
+		f.mu.Unlock()
+		f.scope.leave(f)
 	})
+
+	// callbacks run outside of the once so that they can inspect the future
+	for _, cb := range callbacks {
+		cb()
+	}
+}
+
+// poll resolves the future when that can happen without blocking
+func (f *future[T]) poll() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+	}
+	if f.ctx.Err() == nil && len(f.C) == 0 {
+		return false
+	}
+	f.resolve()
+	return true
+}
+
+func (f *future[T]) Get() (T, context.Context, error) {
+	f.begin()
+	f.resolve()
 	if f.val == nil {
 		var zeroT T
 		return zeroT, f.ctx, nil
@@ -96,76 +231,201 @@ func (f *future[T]) Get() (T, context.Context, error) {
 	return f.val.Value, f.val.Ctx, f.val.Err
 }
 
-func (f *future[T]) Cancel() {
-	f.cancel()
+// GetContext waits for the future like Get, but stops waiting when the context is done.
+// In that case the context error is returned and the future keeps running.
+func (f *future[T]) GetContext(ctx context.Context) (T, context.Context, error) {
+	f.begin()
+	if f.poll() {
+		return f.Get()
+	}
+	select {
+	case <-f.Done():
+		return f.Get()
+	case <-ctx.Done():
+		var zeroT T
+		return zeroT, ctx, ctx.Err()
+	}
 }
 
-func (f *future[T]) AndThen(fn func(context.Context, T) (T, context.Context, error)) Future[T] {
-	c := make(chan result[T], 1)
-	go func() {
-		defer close(c)
+// TryGet returns the outcome of the future without blocking, the value is none while the future is pending
+func (f *future[T]) TryGet() (opt.Option[T], error) {
+	if !f.poll() {
+		return opt.None[T](), nil
+	}
+	v, _, err := f.Get()
+	if err != nil {
+		return opt.None[T](), err
+	}
+	return opt.Some(v), nil
+}
+
+// Done returns a channel that is closed when the future completes
+func (f *future[T]) Done() <-chan struct{} {
+	f.begin()
+	f.poll()
+	return f.done
+}
 
-		v, ctx, e := f.Get()
-		if e != nil { // on error we fail here
-			c <- result[T]{Value: v, Ctx: ctx, Err: e}
-			return
+// IsDone returns true when the future has completed
+func (f *future[T]) IsDone() bool {
+	return f.poll()
+}
+
+// OnSuccess registers a callback that is invoked with the value when the future succeeds
+func (f *future[T]) OnSuccess(fn func(T), executor ...Executor) {
+	f.OnComplete(func(v T, err error) {
+		if err == nil {
+			fn(v)
 		}
+	}, executor...)
+}
 
-		select {
-		case <-ctx.Done():
-			c <- result[T]{Value: v, Ctx: ctx, Err: ctx.Err()}
-			select {
-			case <-f.ctx.Done():
-			default:
-				f.cancel() // ensure closed if out of scope
+// OnFailure registers a callback that is invoked with the error when the future fails
+func (f *future[T]) OnFailure(fn func(error), executor ...Executor) {
+	f.OnComplete(func(_ T, err error) {
+		if err != nil {
+			fn(err)
+		}
+	}, executor...)
+}
+
+// OnComplete registers a callback that is invoked exactly once when the future completes.
+// When the future has already completed the callback is invoked immediately.
+// Without an executor the callback runs on the go routine that observes the completion.
+// A panic in the callback is recovered and reported to the callback error handler.
+func (f *future[T]) OnComplete(fn func(T, error), executor ...Executor) {
+	if len(executor) > 1 {
+		panic("only 1 executor can be specified")
+	}
+
+	cb := func() {
+		defer recoverCallback()
+		v, _, err := f.Get()
+		fn(v, err)
+	}
+	if len(executor) == 1 && executor[0] != nil {
+		exec, run := executor[0], cb
+		cb = func() {
+			if err := exec.Execute(run); err != nil {
+				reportCallbackError(err)
 			}
-		case <-f.ctx.Done():
-			c <- result[T]{Value: v, Ctx: ctx, Err: f.ctx.Err()}
-		default:
-			vv, ctx2, e2 := fn(ctx, v)
-			c <- result[T]{Value: vv, Ctx: ctx2, Err: e2}
 		}
+	}
 
-	}()
-	return &future[T]{
-		C:      c,
-		cancel: f.cancel,
-		ctx:    f.ctx,
-		once:   new(sync.Once),
+	f.mu.Lock()
+	select {
+	case <-f.done:
+		f.mu.Unlock()
+		cb()
+		return
+	default:
 	}
+	f.callbacks = append(f.callbacks, cb)
+	f.mu.Unlock()
 }
 
-func (f *future[T]) OrElse(fn func(context.Context, error) (T, context.Context, error)) Future[T] {
-	c := make(chan result[T], 1)
-	go func() {
-		defer close(c)
+// State returns the state of the future without blocking
+func (f *future[T]) State() State {
+	if !f.poll() {
+		return Pending
+	}
+	return stateOf(f.val.Err)
+}
 
-		v, ctx, e := f.Get()
-		if e == nil { // on error we fail here
-			c <- result[T]{Value: v, Ctx: ctx}
-			return
-		}
+func (f *future[T]) Cancel() {
+	f.cancel()
+	f.poll() // a lazy future that didn't start isn't resolved by its scope
+}
+
+// AndThen creates a future that applies the continuation to the value of the future when it succeeds.
+// The continuation runs on the provided executor, or on the executor of the future when none is provided.
+// When that executor rejects the continuation it runs in the go routine that completed the future.
+func (f *future[T]) AndThen(fn func(context.Context, T) (T, context.Context, error), executor ...Executor) Future[T] {
+	return then[T, T](f, fn, hie.Identity[T], executor...)
+}
+
+// OrElse creates a future that applies the error handler to the error of the future when it fails.
+// The handler runs on the provided executor, or on the executor of the future when none is provided.
+// When that executor rejects the handler it runs in the go routine that completed the future.
+func (f *future[T]) OrElse(fn func(context.Context, error) (T, context.Context, error), executor ...Executor) Future[T] {
+	nf := newFuture[T](scopeOf[T](f), executorOf[T](f, executor...))
+	deferTo[T, T](nf, f)
+	f.OnComplete(func(T, error) {
+		nf.follow(func() result[T] {
+			v, ctx, e := f.Get()
+			if e == nil { // on success we pass the value along
+				return result[T]{Value: v, Ctx: ctx}
+			}
 
-		select {
-		case <-ctx.Done():
-			c <- result[T]{Value: v, Ctx: ctx, Err: ctx.Err()}
 			select {
+			case <-ctx.Done():
+				select {
+				case <-f.ctx.Done():
+				default:
+					f.cancel() // ensure closed if out of scope
+				}
+				return result[T]{Value: v, Ctx: ctx, Err: ctx.Err()}
 			case <-f.ctx.Done():
+				return result[T]{Value: v, Ctx: ctx, Err: f.ctx.Err()}
 			default:
-				f.cancel() // ensure closed if out of scope
+				vv, ctx2, e2 := protect(ctx, func() (T, context.Context, error) { return fn(ctx, e) })
+				return result[T]{Value: vv, Ctx: ctx2, Err: e2}
 			}
-		case <-f.ctx.Done():
-			c <- result[T]{Value: v, Ctx: ctx, Err: f.ctx.Err()}
-		default:
-			vv, ctx2, e2 := fn(ctx, e)
-			c <- result[T]{Value: vv, Ctx: ctx2, Err: e2}
-		}
+		})
+	})
+	return nf
+}
 
-	}()
-	return &future[T]{
-		C:      c,
-		cancel: f.cancel,
-		ctx:    f.ctx,
-		once:   new(sync.Once),
+// deferTo makes the continuation lazy when the future it continues is lazy,
+// waiting on the continuation then starts that future. Otherwise the continuation joins its scope right away.
+func deferTo[T, R any](continuation *future[R], f Future[T]) {
+	if ff, ok := f.(*future[T]); ok && ff.launch != nil {
+		continuation.launch = ff.begin
+		return
+	}
+	continuation.begin()
+}
+
+// executorOf picks the executor for a continuation, it defaults to the executor of the future
+func executorOf[T any](f Future[T], executor ...Executor) Executor {
+	if len(executor) > 1 {
+		panic("only 1 executor can be specified")
+	}
+	if len(executor) == 1 && executor[0] != nil {
+		return executor[0]
 	}
+	if ff, ok := f.(*future[T]); ok {
+		return ff.exec
+	}
+	return nil
+}
+
+// then runs the continuation when the future succeeds, carry converts the value of the future when the continuation is skipped
+func then[T, R any](f Future[T], fn func(context.Context, T) (R, context.Context, error), carry func(T) R, executor ...Executor) Future[R] {
+	nf := newFuture[R](scopeOf(f), executorOf(f, executor...))
+	deferTo(nf, f)
+	f.OnComplete(func(T, error) {
+		nf.follow(func() result[R] {
+			v, ctx, e := f.Get()
+			if e != nil { // on error we fail here
+				return result[R]{Value: carry(v), Ctx: ctx, Err: e}
+			}
+
+			select {
+			case <-ctx.Done():
+				select {
+				case <-nf.ctx.Done():
+				default:
+					nf.cancel() // ensure closed if out of scope
+				}
+				return result[R]{Value: carry(v), Ctx: ctx, Err: ctx.Err()}
+			case <-nf.ctx.Done():
+				return result[R]{Value: carry(v), Ctx: ctx, Err: nf.ctx.Err()}
+			default:
+				vv, ctx2, e2 := protect(ctx, func() (R, context.Context, error) { return fn(ctx, v) })
+				return result[R]{Value: vv, Ctx: ctx2, Err: e2}
+			}
+		})
+	})
+	return nf
 }
@@ -0,0 +1,84 @@
+package future_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/casualjim/hie/future"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func counted(count *int64, v int) func(context.Context) (int, context.Context, error) {
+	return func(ctx context.Context) (int, context.Context, error) {
+		atomic.AddInt64(count, 1)
+		return v, ctx, nil
+	}
+}
+
+func TestLazy(t *testing.T) {
+	t.Parallel()
+
+	var count int64
+	f := future.Lazy(counted(&count, 5))
+	f.OnSuccess(func(int) {})
+	assert.False(t, f.IsDone())
+	assert.Equal(t, future.Pending, f.State())
+	assert.EqualValues(t, 0, atomic.LoadInt64(&count))
+
+	v, _, err := f.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 5, v)
+	f.Get()
+	assert.EqualValues(t, 1, atomic.LoadInt64(&count))
+}
+
+func TestLazy_Done(t *testing.T) {
+	t.Parallel()
+
+	var count int64
+	f := future.Lazy(counted(&count, 5))
+	<-f.Done()
+	assert.EqualValues(t, 1, atomic.LoadInt64(&count))
+}
+
+func TestLazy_Chain(t *testing.T) {
+	t.Parallel()
+
+	var count int64
+	f := future.Lazy(counted(&count, 1)).
+		AndThen(func(ctx context.Context, i int) (int, context.Context, error) {
+			atomic.AddInt64(&count, 1)
+			return i + 1, ctx, nil
+		}).
+		OrElse(future.ElseFunc(func(error) (int, error) { return 0, nil }))
+	g := future.Map(f, func(i int) int { return i * 10 })
+	assert.EqualValues(t, 0, atomic.LoadInt64(&count))
+
+	v, _, err := g.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 20, v)
+	assert.EqualValues(t, 2, atomic.LoadInt64(&count))
+}
+
+func TestLazy_Cancel(t *testing.T) {
+	t.Parallel()
+
+	var count int64
+	f := future.Lazy(counted(&count, 1))
+	f.Cancel()
+	_, _, err := f.Get()
+	assert.Equal(t, context.Canceled, err)
+	assert.EqualValues(t, 0, atomic.LoadInt64(&count))
+}
+
+func TestLazy_Combinators(t *testing.T) {
+	t.Parallel()
+
+	var count int64
+	values, _, err := future.All(future.Lazy(counted(&count, 1)), future.Lazy(counted(&count, 2))).Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, values)
+	assert.EqualValues(t, 2, atomic.LoadInt64(&count))
+}
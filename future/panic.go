@@ -0,0 +1,65 @@
+package future
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// PanicError is a panic that was recovered from a future function or a callback,
+// it carries the recovered value and the stack trace of the panic
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func newPanicError(recovered any) *PanicError {
+	return &PanicError{Value: recovered, Stack: debug.Stack()}
+}
+
+// protect invokes the function and turns a panic into a *PanicError
+func protect[T any](ctx context.Context, fn func() (T, context.Context, error)) (value T, rctx context.Context, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			value, rctx, err = zero, ctx, newPanicError(r)
+		}
+	}()
+	return fn()
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+// Unwrap returns the recovered value when it is an error
+func (p *PanicError) Unwrap() error {
+	if err, ok := p.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+var callbackErrorHandler atomic.Value
+
+// SetCallbackErrorHandler replaces the function that receives panics from completion callbacks as a *PanicError
+// and errors from executors that rejected a callback. By default these are written to the standard logger.
+func SetCallbackErrorHandler(fn func(error)) {
+	callbackErrorHandler.Store(fn)
+}
+
+func reportCallbackError(err error) {
+	if fn, ok := callbackErrorHandler.Load().(func(error)); ok && fn != nil {
+		fn(err)
+		return
+	}
+	log.Printf("future: callback failed: %v", err)
+}
+
+func recoverCallback() {
+	if r := recover(); r != nil {
+		reportCallbackError(newPanicError(r))
+	}
+}
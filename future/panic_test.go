@@ -0,0 +1,79 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/casualjim/hie/future"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo_Panic(t *testing.T) {
+	t.Parallel()
+
+	f := future.Do(future.Func(func() (int, error) {
+		panic("boom")
+	}))
+
+	_, ctx, err := f.Get()
+	var pe *future.PanicError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "boom", pe.Value)
+	assert.Contains(t, string(pe.Stack), "panic_test.go")
+	assert.Equal(t, "panic: boom", pe.Error())
+	assert.NotNil(t, ctx)
+}
+
+func TestDo_PanicWithError(t *testing.T) {
+	t.Parallel()
+
+	exp := errors.New("expected")
+	f := future.Do(future.Func(func() (int, error) {
+		panic(exp)
+	}))
+
+	_, _, err := f.Get()
+	assert.ErrorIs(t, err, exp)
+}
+
+func TestAndThen_Panic(t *testing.T) {
+	t.Parallel()
+
+	f := value(1).AndThen(future.ThenFunc(func(i int) (int, error) {
+		var m map[string]int
+		m["a"] = i // nil map panics
+		return i, nil
+	}))
+
+	_, _, err := f.Get()
+	var pe *future.PanicError
+	require.ErrorAs(t, err, &pe)
+}
+
+func TestOrElse_HandlesPanic(t *testing.T) {
+	t.Parallel()
+
+	f := future.Do(func(ctx context.Context) (int, context.Context, error) {
+		panic("boom")
+	}).OrElse(future.ElseFunc(func(err error) (int, error) {
+		var pe *future.PanicError
+		if errors.As(err, &pe) {
+			return 42, nil
+		}
+		return 0, err
+	}))
+
+	v, _, err := f.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 42, v)
+
+	g := failure(errors.New("first")).OrElse(future.ElseFunc(func(err error) (int, error) {
+		panic("second")
+	}))
+	_, _, err = g.Get()
+	var pe *future.PanicError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "second", pe.Value)
+}
@@ -0,0 +1,69 @@
+package future
+
+import (
+	"context"
+	"sync"
+)
+
+// NewPromise creates a promise whose future is completed from the outside
+func NewPromise[T any]() *Promise[T] {
+	return NewPromiseWithContext[T](context.Background())
+}
+
+// NewPromiseWithContext creates a promise whose future is completed from the outside.
+// The future is cancelled when the context is done.
+func NewPromiseWithContext[T any](ctx context.Context) *Promise[T] {
+	f := newFuture[T](newScope(ctx), nil)
+	f.begin()
+	return &Promise[T]{future: f}
+}
+
+// Promise is the write side of a future, the first completion wins and later completions are ignored.
+// Cancelling the future is signalled to the owner of the promise through its context.
+type Promise[T any] struct {
+	mu        sync.Mutex
+	future    *future[T]
+	completed bool
+}
+
+// Future returns the read side of the promise
+func (p *Promise[T]) Future() Future[T] {
+	return p.future
+}
+
+// Context returns a context that is done when the future is cancelled
+func (p *Promise[T]) Context() context.Context {
+	return p.future.ctx
+}
+
+// Resolve completes the future with a value
+func (p *Promise[T]) Resolve(value T) {
+	p.TryResolve(value)
+}
+
+// Reject completes the future with an error
+func (p *Promise[T]) Reject(err error) {
+	p.TryReject(err)
+}
+
+// TryResolve completes the future with a value, it returns false when the future was already completed or cancelled
+func (p *Promise[T]) TryResolve(value T) bool {
+	return p.complete(result[T]{Value: value, Ctx: p.future.ctx})
+}
+
+// TryReject completes the future with an error, it returns false when the future was already completed or cancelled
+func (p *Promise[T]) TryReject(err error) bool {
+	return p.complete(result[T]{Err: err, Ctx: p.future.ctx})
+}
+
+func (p *Promise[T]) complete(r result[T]) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.completed || p.future.ctx.Err() != nil {
+		return false
+	}
+	p.completed = true
+	p.future.complete(r)
+	return true
+}
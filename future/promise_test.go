@@ -0,0 +1,77 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/casualjim/hie/future"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromise_Resolve(t *testing.T) {
+	t.Parallel()
+
+	p := future.NewPromise[int]()
+	go p.Resolve(5)
+
+	v, ctx, err := p.Future().Get()
+	require.NoError(t, err)
+	assert.Equal(t, 5, v)
+	assert.NotNil(t, ctx)
+
+	assert.False(t, p.TryResolve(6))
+	assert.False(t, p.TryReject(errors.New("late")))
+	v, _, err = p.Future().Get()
+	require.NoError(t, err)
+	assert.Equal(t, 5, v)
+}
+
+func TestPromise_Reject(t *testing.T) {
+	t.Parallel()
+
+	exp := errors.New("expected")
+	p := future.NewPromise[int]()
+	assert.True(t, p.TryReject(exp))
+	p.Resolve(1)
+
+	_, _, err := p.Future().Get()
+	assert.Equal(t, exp, err)
+}
+
+func TestPromise_Cancel(t *testing.T) {
+	t.Parallel()
+
+	p := future.NewPromise[int]()
+	p.Future().Cancel()
+
+	<-p.Context().Done()
+	assert.False(t, p.TryResolve(1))
+	_, _, err := p.Future().Get()
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestPromise_ParentContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := future.NewPromiseWithContext[int](ctx)
+	cancel()
+
+	<-p.Context().Done()
+	_, _, err := p.Future().Get()
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestPromise_Chaining(t *testing.T) {
+	t.Parallel()
+
+	p := future.NewPromise[int]()
+	f := p.Future().AndThen(future.ThenFunc(func(i int) (int, error) { return i * 2, nil }))
+	p.Resolve(4)
+
+	v, _, err := f.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 8, v)
+}
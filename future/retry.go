@@ -0,0 +1,131 @@
+package future
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/casualjim/hie/clock"
+)
+
+// Attempt describes an invocation of a function that is being retried
+type Attempt struct {
+	// Number of this attempt, the first attempt is 1
+	Number int
+	// Start is the time at which the first attempt started
+	Start time.Time
+	// Elapsed is the time that passed since the first attempt started
+	Elapsed time.Duration
+	// LastErr is the error returned by the previous attempt
+	LastErr error
+}
+
+// Backoff computes how long to wait after the given attempt failed
+type Backoff interface {
+	Delay(attempt int) time.Duration
+}
+
+// BackoffFunc adapts a function to the Backoff interface
+type BackoffFunc func(attempt int) time.Duration
+
+func (b BackoffFunc) Delay(attempt int) time.Duration { return b(attempt) }
+
+// ConstantBackoff waits the same duration between every attempt
+func ConstantBackoff(d time.Duration) Backoff {
+	return BackoffFunc(func(int) time.Duration { return d })
+}
+
+// ExponentialBackoff multiplies the delay after every failed attempt.
+// The jitter is the fraction of the delay that is randomized, so a jitter of 0.5 with a delay of 1s
+// results in a delay between 500ms and 1.5s.
+type ExponentialBackoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64 // defaults to 2
+	Jitter     float64
+	Rand       func() float64 // defaults to math/rand.Float64
+}
+
+func (e ExponentialBackoff) Delay(attempt int) time.Duration {
+	mult := e.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+	delay := float64(e.Initial) * math.Pow(mult, float64(attempt-1))
+	if e.Max > 0 && delay > float64(e.Max) {
+		delay = float64(e.Max)
+	}
+	if e.Jitter > 0 {
+		rnd := e.Rand
+		if rnd == nil {
+			rnd = rand.Float64
+		}
+		delay += delay * e.Jitter * (2*rnd() - 1)
+	}
+	if delay < 0 {
+		return 0
+	}
+	return time.Duration(delay)
+}
+
+// RetryPolicy decides if and when a failed attempt is retried.
+// The zero value retries every error immediately until the function succeeds or the context is done.
+type RetryPolicy struct {
+	// Backoff computes the delay before the next attempt, nil means no delay
+	Backoff Backoff
+	// MaxAttempts limits the number of attempts, 0 means unlimited
+	MaxAttempts int
+	// MaxElapsed stops retrying when the next attempt would start after this duration, 0 means unlimited
+	MaxElapsed time.Duration
+	// Retryable classifies errors, nil means every error is retryable
+	Retryable func(error) bool
+	// Clock is used for the delays between attempts, nil means the wall clock
+	Clock clock.Clock
+}
+
+func (p RetryPolicy) delay(attempt Attempt) (time.Duration, bool) {
+	if p.MaxAttempts > 0 && attempt.Number >= p.MaxAttempts {
+		return 0, false
+	}
+	if p.Retryable != nil && !p.Retryable(attempt.LastErr) {
+		return 0, false
+	}
+
+	var delay time.Duration
+	if p.Backoff != nil {
+		delay = p.Backoff.Delay(attempt.Number)
+	}
+	if p.MaxElapsed > 0 && attempt.Elapsed+delay > p.MaxElapsed {
+		return 0, false
+	}
+	return delay, true
+}
+
+// Retry creates a future that executes the function in a go routine until it succeeds or the policy gives up.
+// The future fails with the error of the last attempt, or with the context error when the context is done between attempts.
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func(context.Context, Attempt) (T, context.Context, error)) Future[T] {
+	clk := clock.Or(policy.Clock)
+	return DoWithContext(ctx, func(ctx context.Context) (T, context.Context, error) {
+		attempt := Attempt{Number: 1, Start: clk.Now()}
+		for {
+			v, rctx, err := fn(ctx, attempt)
+			if err == nil {
+				return v, rctx, nil
+			}
+
+			attempt.LastErr = err
+			attempt.Elapsed = clk.Now().Sub(attempt.Start)
+			delay, retry := policy.delay(attempt)
+			if !retry {
+				return v, rctx, err
+			}
+			if err := clock.Sleep(ctx, clk, delay); err != nil {
+				return v, ctx, err
+			}
+
+			attempt.Number++
+			attempt.Elapsed = clk.Now().Sub(attempt.Start)
+		}
+	})
+}
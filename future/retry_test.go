@@ -0,0 +1,134 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/casualjim/hie/clock"
+	"github.com/casualjim/hie/future"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry_Success(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	transient := errors.New("transient")
+	var attempts []future.Attempt
+
+	f := future.Retry(context.Background(), future.RetryPolicy{
+		Backoff: future.ConstantBackoff(time.Second),
+		Clock:   clk,
+	}, func(ctx context.Context, a future.Attempt) (int, context.Context, error) {
+		attempts = append(attempts, a)
+		if a.Number < 3 {
+			return 0, ctx, transient
+		}
+		return a.Number, ctx, nil
+	})
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+
+	v, _, err := f.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+	require.Len(t, attempts, 3)
+	assert.Nil(t, attempts[0].LastErr)
+	assert.Equal(t, transient, attempts[1].LastErr)
+	assert.Equal(t, 2*time.Second, attempts[2].Elapsed)
+}
+
+func TestRetry_MaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	exp := errors.New("expected")
+	var count int
+	f := future.Retry(context.Background(), future.RetryPolicy{MaxAttempts: 4}, func(ctx context.Context, a future.Attempt) (int, context.Context, error) {
+		count++
+		return 0, ctx, exp
+	})
+
+	_, _, err := f.Get()
+	assert.Equal(t, exp, err)
+	assert.Equal(t, 4, count)
+}
+
+func TestRetry_Retryable(t *testing.T) {
+	t.Parallel()
+
+	transient, permanent := errors.New("transient"), errors.New("permanent")
+	var count int
+	f := future.Retry(context.Background(), future.RetryPolicy{
+		Retryable: func(err error) bool { return errors.Is(err, transient) },
+	}, func(ctx context.Context, a future.Attempt) (int, context.Context, error) {
+		count++
+		if a.Number == 1 {
+			return 0, ctx, transient
+		}
+		return 0, ctx, permanent
+	})
+
+	_, _, err := f.Get()
+	assert.Equal(t, permanent, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestRetry_MaxElapsed(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	exp := errors.New("expected")
+	f := future.Retry(context.Background(), future.RetryPolicy{
+		Backoff:    future.ConstantBackoff(time.Minute),
+		MaxElapsed: 90 * time.Second,
+		Clock:      clk,
+	}, func(ctx context.Context, a future.Attempt) (int, context.Context, error) {
+		return a.Number, ctx, exp
+	})
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Minute)
+
+	v, _, err := f.Get()
+	assert.Equal(t, exp, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestRetry_Cancel(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	f := future.Retry(context.Background(), future.RetryPolicy{
+		Backoff: future.ConstantBackoff(time.Minute),
+		Clock:   clk,
+	}, func(ctx context.Context, a future.Attempt) (int, context.Context, error) {
+		return 0, ctx, errors.New("transient")
+	})
+
+	clk.BlockUntil(1)
+	f.Cancel()
+	_, _, err := f.Get()
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	b := future.ExponentialBackoff{Initial: 100 * time.Millisecond, Max: time.Second}
+	assert.Equal(t, 100*time.Millisecond, b.Delay(1))
+	assert.Equal(t, 200*time.Millisecond, b.Delay(2))
+	assert.Equal(t, 400*time.Millisecond, b.Delay(3))
+	assert.Equal(t, time.Second, b.Delay(10))
+
+	b.Jitter = 0.5
+	b.Rand = func() float64 { return 0 }
+	assert.Equal(t, 50*time.Millisecond, b.Delay(1))
+	b.Rand = func() float64 { return 1 }
+	assert.Equal(t, 150*time.Millisecond, b.Delay(1))
+}
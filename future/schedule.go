@@ -0,0 +1,95 @@
+package future
+
+import (
+	"context"
+	"time"
+
+	"github.com/casualjim/hie"
+	"github.com/casualjim/hie/clock"
+)
+
+// After creates a future that executes the function in a go routine once the delay has passed.
+// Cancelling the future during the delay prevents the function from running.
+// An optional clock can be provided to control the passing of time, it defaults to the wall clock.
+func After[T any](d time.Duration, fn func(context.Context) (T, context.Context, error), clk ...clock.Clock) Future[T] {
+	c := clock.Or(clk...)
+	return Do(func(ctx context.Context) (T, context.Context, error) {
+		if err := clock.Sleep(ctx, c, d); err != nil {
+			var zero T
+			return zero, ctx, err
+		}
+		return fn(ctx)
+	})
+}
+
+// Schedule creates a future that executes the function in a go routine at the provided time.
+// When that time has already passed the function runs right away.
+func Schedule[T any](at time.Time, fn func(context.Context) (T, context.Context, error), clk ...clock.Clock) Future[T] {
+	c := clock.Or(clk...)
+	return After(at.Sub(c.Now()), fn, c)
+}
+
+// Every returns an iterator that executes the function every interval and yields its outcomes.
+// The function runs when HasNext is called and the next tick is due, ticks that were missed by a slow consumer are skipped.
+// The iterator ends when the context is done or when it is closed.
+func Every[T any](ctx context.Context, interval time.Duration, fn func(context.Context) (T, context.Context, error), clk ...clock.Clock) hie.Iter[Settled[T]] {
+	if interval <= 0 {
+		panic("the interval must be positive")
+	}
+	c := clock.Or(clk...)
+	inner, cancel := context.WithCancel(ctx)
+	return &everyIter[T]{
+		ctx:      inner,
+		cancel:   cancel,
+		clock:    c,
+		interval: interval,
+		next:     c.Now().Add(interval),
+		fn:       fn,
+	}
+}
+
+type everyIter[T any] struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	clock    clock.Clock
+	interval time.Duration
+	next     time.Time
+	fn       func(context.Context) (T, context.Context, error)
+	pending  *Settled[T]
+}
+
+func (e *everyIter[T]) HasNext() bool {
+	if e.pending != nil {
+		return true
+	}
+	if e.ctx.Err() != nil {
+		return false
+	}
+
+	if err := clock.Sleep(e.ctx, e.clock, e.next.Sub(e.clock.Now())); err != nil {
+		return false
+	}
+	now := e.clock.Now()
+	for !e.next.After(now) {
+		e.next = e.next.Add(e.interval)
+	}
+
+	v, _, err := protect(e.ctx, func() (T, context.Context, error) { return e.fn(e.ctx) })
+	e.pending = &Settled[T]{Value: v, Err: err}
+	return true
+}
+
+func (e *everyIter[T]) Next() Settled[T] {
+	if !e.HasNext() {
+		panic("iterating beyond end")
+	}
+	res := e.pending
+	e.pending = nil
+	return *res
+}
+
+func (e *everyIter[T]) Close() error {
+	e.cancel()
+	e.pending = nil
+	return nil
+}
@@ -0,0 +1,116 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/casualjim/hie/clock"
+	"github.com/casualjim/hie/future"
+	"github.com/casualjim/hie/iter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAfter(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	f := future.After(time.Minute, future.Func(func() (int, error) { return 7, nil }), clk)
+
+	clk.BlockUntil(1)
+	assert.Equal(t, future.Pending, f.State())
+	clk.Advance(59 * time.Second)
+	assert.Equal(t, future.Pending, f.State())
+	clk.Advance(time.Second)
+
+	v, _, err := f.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 7, v)
+}
+
+func TestAfter_Cancel(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	ran := make(chan struct{}, 1)
+	f := future.After(time.Minute, future.Func(func() (int, error) {
+		ran <- struct{}{}
+		return 7, nil
+	}), clk)
+
+	clk.BlockUntil(1)
+	f.Cancel()
+	_, _, err := f.Get()
+	assert.Equal(t, context.Canceled, err)
+
+	clk.Advance(time.Hour)
+	assert.Empty(t, ran)
+}
+
+func TestSchedule(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	f := future.Schedule(clk.Now().Add(time.Hour), future.Func(func() (int, error) { return 1, nil }), clk)
+	clk.BlockUntil(1)
+	clk.Advance(time.Hour)
+	v, _, err := f.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	past := future.Schedule(clk.Now().Add(-time.Hour), future.Func(func() (int, error) { return 2, nil }), clk)
+	v, _, err = past.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestEvery(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	exp := errors.New("odd")
+	var count int
+	it := future.Every(context.Background(), time.Second, future.Func(func() (int, error) {
+		count++
+		if count%2 == 1 {
+			return 0, exp
+		}
+		return count, nil
+	}), clk)
+
+	results := make(chan future.Settled[int])
+	go func() {
+		for i := 0; i < 3 && it.HasNext(); i++ {
+			results <- it.Next()
+		}
+		close(results)
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	assert.Equal(t, future.Settled[int]{Err: exp}, <-results)
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	assert.Equal(t, future.Settled[int]{Value: 2}, <-results)
+	clk.BlockUntil(1)
+	clk.Advance(3 * time.Second) // missed ticks are skipped
+	assert.Equal(t, future.Settled[int]{Err: exp}, <-results)
+	_, open := <-results
+	assert.False(t, open)
+}
+
+func TestEvery_Close(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	it := future.Every(context.Background(), time.Second, future.Func(func() (int, error) { return 1, nil }), clk)
+
+	done := make(chan bool)
+	go func() { done <- it.HasNext() }()
+	clk.BlockUntil(1)
+	require.NoError(t, iter.Close(it))
+	assert.False(t, <-done)
+	assert.False(t, it.HasNext())
+}
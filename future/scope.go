@@ -0,0 +1,156 @@
+package future
+
+import (
+	"context"
+	"sync"
+)
+
+// scope is the cancellation scope that a future shares with its continuations.
+// Cancelling the scope resolves all the futures in it, so nobody needs to wait on the context in a go routine.
+type scope struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	external <-chan struct{}
+	onCancel func()
+
+	mu       sync.Mutex
+	members  map[member]struct{}
+	watching bool
+}
+
+// member is a future that is resolved when its scope is cancelled
+type member interface {
+	resolve()
+}
+
+func newScope(parent context.Context) *scope {
+	ctx, cancel := context.WithCancel(parent)
+	return &scope{
+		ctx:      ctx,
+		cancel:   cancel,
+		external: parent.Done(),
+		members:  make(map[member]struct{}),
+	}
+}
+
+// scopeOf returns the scope of the future, a future from another implementation gets a scope that cancels it
+func scopeOf[T any](f Future[T]) *scope {
+	if ff, ok := f.(*future[T]); ok {
+		return ff.scope
+	}
+	s := newScope(context.Background())
+	s.onCancel = f.Cancel
+	return s
+}
+
+// join adds a pending future to the scope, the future calls leave once it is resolved.
+// While a scope with a cancellable parent has pending futures, it is watched for the cancellation of that parent.
+func (s *scope) join(m member) {
+	s.mu.Lock()
+	if s.ctx.Err() == nil {
+		s.members[m] = struct{}{}
+		if s.external != nil && !s.watching {
+			s.watching = true
+			watch(s)
+		}
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+	m.resolve()
+}
+
+// leave removes a resolved future from the scope, it does nothing for a future that isn't in the scope
+func (s *scope) leave(m member) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.members[m]; !ok {
+		return
+	}
+	delete(s.members, m)
+	if len(s.members) == 0 && s.watching {
+		s.watching = false
+		unwatch(s)
+	}
+}
+
+// Cancel cancels the context of the scope and resolves its futures
+func (s *scope) Cancel() {
+	s.mu.Lock()
+	s.cancel()
+	members := s.members
+	s.members = make(map[member]struct{})
+	if s.watching {
+		s.watching = false
+		unwatch(s)
+	}
+	s.mu.Unlock()
+
+	if s.onCancel != nil {
+		s.onCancel()
+	}
+	for m := range members {
+		m.resolve()
+	}
+}
+
+// watchers shares a single go routine per parent context between all the scopes that wait on it
+var watchers = struct {
+	mu sync.Mutex
+	m  map[<-chan struct{}]*watcher
+}{m: make(map[<-chan struct{}]*watcher)}
+
+type watcher struct {
+	scopes map[*scope]struct{}
+	idle   chan struct{}
+}
+
+func watch(s *scope) {
+	watchers.mu.Lock()
+	defer watchers.mu.Unlock()
+	w, ok := watchers.m[s.external]
+	if !ok {
+		w = &watcher{scopes: make(map[*scope]struct{}), idle: make(chan struct{})}
+		watchers.m[s.external] = w
+		go w.run(s.external)
+	}
+	w.scopes[s] = struct{}{}
+}
+
+func unwatch(s *scope) {
+	watchers.mu.Lock()
+	defer watchers.mu.Unlock()
+	w, ok := watchers.m[s.external]
+	if !ok {
+		return
+	}
+	if _, ok := w.scopes[s]; !ok {
+		return
+	}
+	delete(w.scopes, s)
+	if len(w.scopes) == 0 {
+		delete(watchers.m, s.external)
+		close(w.idle)
+	}
+}
+
+// run cancels the watched scopes when the parent context is done, it exits early once no scope is pending anymore
+func (w *watcher) run(done <-chan struct{}) {
+	select {
+	case <-done:
+	case <-w.idle:
+		return
+	}
+
+	watchers.mu.Lock()
+	if watchers.m[done] == w {
+		delete(watchers.m, done)
+	}
+	scopes := w.scopes
+	w.scopes = nil
+	watchers.mu.Unlock()
+
+	for s := range scopes {
+		s.Cancel()
+	}
+}
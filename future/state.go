@@ -0,0 +1,46 @@
+package future
+
+import (
+	"context"
+	"errors"
+)
+
+// State describes the progress of a future
+type State int
+
+const (
+	// Pending futures have not completed yet
+	Pending State = iota
+	// Succeeded futures completed with a value
+	Succeeded
+	// Failed futures completed with an error
+	Failed
+	// Cancelled futures were cancelled before they completed
+	Cancelled
+)
+
+func (s State) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Succeeded:
+		return "succeeded"
+	case Failed:
+		return "failed"
+	case Cancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+func stateOf(err error) State {
+	switch {
+	case err == nil:
+		return Succeeded
+	case errors.Is(err, context.Canceled):
+		return Cancelled
+	default:
+		return Failed
+	}
+}
@@ -0,0 +1,92 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/casualjim/hie/future"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryGet(t *testing.T) {
+	t.Parallel()
+
+	p := future.NewPromise[int]()
+	f := p.Future()
+
+	v, err := f.TryGet()
+	require.NoError(t, err)
+	assert.True(t, v.IsNone())
+	assert.False(t, f.IsDone())
+	assert.Equal(t, future.Pending, f.State())
+
+	p.Resolve(3)
+	v, err = f.TryGet()
+	require.NoError(t, err)
+	assert.Equal(t, 3, v.Value())
+	assert.True(t, f.IsDone())
+	assert.Equal(t, future.Succeeded, f.State())
+
+	exp := errors.New("expected")
+	g := future.NewPromise[int]()
+	g.Reject(exp)
+	v, err = g.Future().TryGet()
+	assert.Equal(t, exp, err)
+	assert.True(t, v.IsNone())
+	assert.Equal(t, future.Failed, g.Future().State())
+}
+
+func TestState_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	f := blocking(nil)
+	f.Cancel()
+	assert.True(t, f.IsDone())
+	assert.Equal(t, future.Cancelled, f.State())
+	assert.Equal(t, "cancelled", f.State().String())
+}
+
+func TestDone(t *testing.T) {
+	t.Parallel()
+
+	p := future.NewPromise[int]()
+	f := p.Future()
+
+	select {
+	case <-f.Done():
+		require.Fail(t, "future should be pending")
+	default:
+	}
+
+	p.Resolve(1)
+	select {
+	case <-f.Done():
+	case <-time.After(time.Second):
+		require.Fail(t, "expected the future to complete")
+	}
+	v, _, err := f.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestGetContext(t *testing.T) {
+	t.Parallel()
+
+	p := future.NewPromise[int]()
+	f := p.Future()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ctx2, err := f.GetContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, ctx, ctx2)
+	assert.Equal(t, future.Pending, f.State(), "the future must not be cancelled")
+
+	p.Resolve(2)
+	v, _, err := f.GetContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
@@ -0,0 +1,78 @@
+package future
+
+import (
+	"context"
+)
+
+// Pair holds the values of 2 futures that were zipped together
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Then creates a future that applies the continuation to the value of the future when it succeeds.
+// Unlike AndThen the continuation can produce a value of a different type.
+// The returned future shares the cancellation scope of the future it continues.
+// The continuation runs on the provided executor, or on the executor of the future when none is provided.
+func Then[T, R any](f Future[T], fn func(context.Context, T) (R, context.Context, error), executor ...Executor) Future[R] {
+	return then(f, fn, func(T) R {
+		var zero R
+		return zero
+	}, executor...)
+}
+
+// Map creates a future that transforms the value of the future when it succeeds
+func Map[T, R any](f Future[T], fn func(T) R, executor ...Executor) Future[R] {
+	return Then(f, func(ctx context.Context, v T) (R, context.Context, error) {
+		return fn(v), ctx, nil
+	}, executor...)
+}
+
+// FlatMap creates a future that continues with the future produced by the continuation when the future succeeds.
+// Cancelling the returned future also cancels the future produced by the continuation.
+// The continuation waits for the future it produces, when that future runs on the same bounded pool as the continuation
+// and the pool has no other worker available the continuation deadlocks. Provide another executor for the continuation in that case.
+func FlatMap[T, R any](f Future[T], fn func(context.Context, T) Future[R], executor ...Executor) Future[R] {
+	return Then(f, func(ctx context.Context, v T) (R, context.Context, error) {
+		next := fn(ctx, v)
+		select {
+		case <-ctx.Done():
+			next.Cancel()
+			var zero R
+			return zero, ctx, ctx.Err()
+		case r := <-settle([]Future[R]{next}):
+			return r.Value, r.Ctx, r.Err
+		}
+	}, executor...)
+}
+
+// Zip creates a future that succeeds with the values of both futures.
+// When one of the futures fails the other one is cancelled.
+// Cancelling the returned future cancels both futures.
+func Zip[A, B any](a Future[A], b Future[B]) Future[Pair[A, B]] {
+	return DoWithContext(context.Background(), func(ctx context.Context) (Pair[A, B], context.Context, error) {
+		ra, rb := settle([]Future[A]{a}), settle([]Future[B]{b})
+		var pair Pair[A, B]
+		for remaining := 2; remaining > 0; remaining-- {
+			select {
+			case <-ctx.Done():
+				a.Cancel()
+				b.Cancel()
+				return Pair[A, B]{}, ctx, ctx.Err()
+			case r := <-ra:
+				if r.Err != nil {
+					b.Cancel()
+					return Pair[A, B]{}, ctx, r.Err
+				}
+				pair.First = r.Value
+			case r := <-rb:
+				if r.Err != nil {
+					a.Cancel()
+					return Pair[A, B]{}, ctx, r.Err
+				}
+				pair.Second = r.Value
+			}
+		}
+		return pair, ctx, nil
+	})
+}
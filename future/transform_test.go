@@ -0,0 +1,112 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/casualjim/hie/future"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThenChangesType(t *testing.T) {
+	t.Parallel()
+
+	f := future.Then(value(21), func(ctx context.Context, i int) (string, context.Context, error) {
+		return strconv.Itoa(i * 2), ctx, nil
+	})
+
+	v, ctx, err := f.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "42", v)
+	assert.NotNil(t, ctx)
+
+	exp := errors.New("expected")
+	var called bool
+	g := future.Then(failure(exp), func(ctx context.Context, i int) (string, context.Context, error) {
+		called = true
+		return "", ctx, nil
+	})
+	v, _, err = g.Get()
+	assert.Equal(t, exp, err)
+	assert.Empty(t, v)
+	assert.False(t, called)
+}
+
+func TestThen_SharesCancellation(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 1)
+	src := blocking(started)
+	f := future.Map(src, strconv.Itoa)
+	<-started
+	f.Cancel()
+
+	_, _, err := f.Get()
+	assert.Equal(t, context.Canceled, err)
+	_, _, err = src.Get()
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	v, _, err := future.Map(future.Map(value(2), strconv.Itoa), func(s string) []byte { return []byte(s) }).Get()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("2"), v)
+}
+
+func TestFlatMap(t *testing.T) {
+	t.Parallel()
+
+	f := future.FlatMap(value(3), func(ctx context.Context, i int) future.Future[string] {
+		return future.DoWithContext(ctx, future.Func(func() (string, error) {
+			return strconv.Itoa(i + 1), nil
+		}))
+	})
+	v, _, err := f.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "4", v)
+
+	exp := errors.New("expected")
+	g := future.FlatMap(value(3), func(ctx context.Context, i int) future.Future[string] {
+		return future.Then(failure(exp), func(ctx context.Context, i int) (string, context.Context, error) {
+			return "", ctx, nil
+		})
+	})
+	_, _, err = g.Get()
+	assert.Equal(t, exp, err)
+}
+
+func TestFlatMap_Cancel(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 1)
+	f := future.FlatMap(value(1), func(ctx context.Context, i int) future.Future[int] {
+		return blocking(started)
+	})
+	<-started
+	f.Cancel()
+
+	_, _, err := f.Get()
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestZip(t *testing.T) {
+	t.Parallel()
+
+	a := value(1)
+	b := future.Map(value(2), strconv.Itoa)
+	v, _, err := future.Zip(a, b).Get()
+	require.NoError(t, err)
+	assert.Equal(t, future.Pair[int, string]{First: 1, Second: "2"}, v)
+
+	exp := errors.New("expected")
+	slow := blocking(nil)
+	_, _, err = future.Zip(slow, failure(exp)).Get()
+	assert.Equal(t, exp, err)
+	_, _, err = slow.Get()
+	assert.Equal(t, context.Canceled, err)
+}
@@ -2,12 +2,14 @@ module github.com/casualjim/hie
 
 go 1.19
 
-require github.com/stretchr/testify v1.8.1
+require (
+	github.com/stretchr/testify v1.8.1
+	go.uber.org/multierr v1.8.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
-	go.uber.org/multierr v1.8.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,246 @@
+package hie
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrHubClosed is returned when publishing to a closed Hub
+var ErrHubClosed = errors.New("hub is closed")
+
+// SlowSubscriberPolicy decides what a Hub does when the buffer of a subscriber is full
+type SlowSubscriberPolicy int
+
+const (
+	// SlowBlock makes the publisher wait until the subscriber has room for the value
+	SlowBlock SlowSubscriberPolicy = iota
+	// SlowDropOldest discards the oldest buffered value of the subscriber to make room for the new one
+	SlowDropOldest
+	// SlowDropNewest discards the value that is being published for this subscriber
+	SlowDropNewest
+	// SlowDisconnect unsubscribes the subscriber, its iterator ends after the buffered values
+	SlowDisconnect
+)
+
+// NewHub creates a Hub without subscribers
+func NewHub[T any]() *Hub[T] {
+	return &Hub[T]{
+		subs: make(map[*subscription[T]]struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Hub broadcasts the published values to all its subscribers.
+// Every subscriber iterates over its own buffered channel, closing the iterator unsubscribes.
+type Hub[T any] struct {
+	mu     sync.RWMutex
+	subs   map[*subscription[T]]struct{}
+	closed bool
+
+	done      chan struct{} // closed when the hub closes, it releases the publishers that are blocked on a subscriber
+	closeDone sync.Once
+}
+
+// Subscribe registers a subscriber with a buffer of bufSize values and the policy to apply when that buffer is full.
+// The returned iterator ends when the hub is closed, the iterator is closed or the subscriber is disconnected.
+func (h *Hub[T]) Subscribe(bufSize int, policy SlowSubscriberPolicy) Iter[T] {
+	if bufSize < 0 {
+		panic("the buffer size can't be negative")
+	}
+	ch := make(chan T, bufSize)
+	sub := &subscription[T]{
+		Iter:   Chan(ch),
+		hub:    h,
+		ch:     ch,
+		policy: policy,
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		sub.closeChan()
+		return sub
+	}
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+// Publish sends the value to all subscribers according to their policy.
+// It only waits for subscribers with the SlowBlock policy. When the context is done while waiting for such a subscriber,
+// that subscriber misses the value, the others still receive it and Publish returns the context error.
+// Publishers don't hold a lock while they wait, so subscribing, unsubscribing and closing the hub never wait for them.
+func (h *Hub[T]) Publish(ctx context.Context, value T) error {
+	h.mu.RLock()
+	if h.closed {
+		h.mu.RUnlock()
+		return ErrHubClosed
+	}
+	subs := make([]*subscription[T], 0, len(h.subs))
+	for sub := range h.subs {
+		subs = append(subs, sub)
+	}
+	h.mu.RUnlock()
+
+	var err error
+	for _, sub := range subs {
+		if sub.send(ctx, h.done, value) {
+			continue
+		}
+		switch {
+		case sub.policy == SlowDisconnect && sub.disconnected.CompareAndSwap(false, true):
+			h.remove(sub)
+		case sub.policy == SlowBlock && err == nil:
+			select {
+			case <-h.done:
+				err = ErrHubClosed
+			default:
+				err = ctx.Err()
+			}
+		}
+	}
+	return err
+}
+
+// Len returns the number of subscribers
+func (h *Hub[T]) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subs)
+}
+
+// Close stops the hub, the iterators of the subscribers end after their buffered values.
+// Publishers that wait for a subscriber are released and return ErrHubClosed.
+func (h *Hub[T]) Close() error {
+	h.closeDone.Do(func() { close(h.done) })
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	subs := h.subs
+	h.subs = make(map[*subscription[T]]struct{})
+	h.mu.Unlock()
+
+	for sub := range subs {
+		sub.closeChan()
+	}
+	return nil
+}
+
+func (h *Hub[T]) remove(sub *subscription[T]) {
+	h.mu.Lock()
+	delete(h.subs, sub)
+	h.mu.Unlock()
+	sub.closeChan()
+}
+
+type subscription[T any] struct {
+	Iter[T]
+	hub    *Hub[T]
+	ch     chan T
+	policy SlowSubscriberPolicy
+
+	// mu is held for reading while sending, so the channel is only closed when no publisher is sending on it
+	mu           sync.RWMutex
+	chClosed     bool
+	done         chan struct{}
+	unsubscribe  sync.Once
+	disconnected atomic.Bool
+	closed       atomic.Bool // set by Close, which may run concurrently with the consumer
+	ready        bool        // HasNext received a value that Next didn't return yet
+}
+
+// send delivers the value according to the policy, it reports false when the value was not delivered
+func (s *subscription[T]) send(ctx context.Context, hubDone <-chan struct{}, value T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.chClosed {
+		return false
+	}
+
+	select {
+	case s.ch <- value:
+		return true
+	default:
+	}
+
+	switch s.policy {
+	case SlowDropOldest:
+		if cap(s.ch) == 0 {
+			return false // there is nothing buffered that can be dropped
+		}
+		for {
+			select {
+			case s.ch <- value:
+				return true
+			default:
+			}
+			select {
+			case <-s.ch:
+			default:
+			}
+		}
+	case SlowBlock:
+		select {
+		case s.ch <- value:
+			return true
+		case <-s.done:
+		case <-hubDone:
+		case <-ctx.Done():
+		}
+	}
+	return false
+}
+
+// closeChan ends the iteration of the subscriber after the buffered values,
+// blocked publishers must be released before, with the done channel of the subscriber or the hub
+func (s *subscription[T]) closeChan() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.chClosed {
+		s.chClosed = true
+		close(s.ch)
+	}
+}
+
+// HasNext waits for the next value, closing the subscription from another go routine makes it return false
+func (s *subscription[T]) HasNext() bool {
+	if s.ready {
+		return true
+	}
+	if s.closed.Load() || !s.Iter.HasNext() {
+		return false
+	}
+	s.ready = true
+	return true
+}
+
+// Next returns the value received by HasNext, even when the subscription was closed since
+func (s *subscription[T]) Next() T {
+	if !s.ready {
+		if s.closed.Load() {
+			panic("next called on a closed iterator")
+		}
+		if !s.HasNext() {
+			panic("iterating beyond end")
+		}
+	}
+	s.ready = false
+	return s.Iter.Next()
+}
+
+// Close unsubscribes from the hub, the values that are still buffered are discarded.
+// It's safe to call from another go routine than the one consuming the subscription.
+func (s *subscription[T]) Close() error {
+	s.closed.Store(true)
+	s.unsubscribe.Do(func() {
+		close(s.done) // releases a publisher that is blocked on this subscriber
+		s.hub.remove(s)
+	})
+	return nil
+}
@@ -0,0 +1,181 @@
+package hie
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub(t *testing.T) {
+	hub := NewHub[int]()
+	a := hub.Subscribe(10, SlowBlock)
+	b := hub.Subscribe(10, SlowDropNewest)
+	require.Equal(t, 2, hub.Len())
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, hub.Publish(context.Background(), i))
+	}
+	require.NoError(t, hub.Close())
+	require.NoError(t, hub.Close())
+	require.ErrorIs(t, hub.Publish(context.Background(), 4), ErrHubClosed)
+
+	require.Equal(t, []int{1, 2, 3}, collect(a))
+	require.Equal(t, []int{1, 2, 3}, collect(b))
+	require.Zero(t, hub.Len())
+
+	late := hub.Subscribe(1, SlowBlock)
+	require.False(t, late.HasNext())
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	hub := NewHub[string]()
+	sub := hub.Subscribe(1, SlowBlock)
+	require.NoError(t, hub.Publish(context.Background(), "a"))
+
+	require.NoError(t, sub.(io.Closer).Close())
+	require.NoError(t, sub.(io.Closer).Close())
+	require.Zero(t, hub.Len())
+	require.False(t, sub.HasNext())
+	require.Panics(t, func() { sub.Next() })
+	require.NoError(t, hub.Publish(context.Background(), "b"))
+}
+
+func TestHub_ConcurrentUnsubscribe(t *testing.T) {
+	hub := NewHub[int]()
+	sub := hub.Subscribe(1, SlowBlock)
+
+	received := make(chan int, 1)
+	consumed := make(chan struct{})
+	go func() {
+		defer close(consumed)
+		for sub.HasNext() {
+			select {
+			case received <- sub.Next():
+			default:
+			}
+		}
+	}()
+
+	publishing := make(chan struct{})
+	go func() {
+		defer close(publishing)
+		for i := 0; i < 1000; i++ {
+			_ = hub.Publish(context.Background(), i)
+		}
+	}()
+
+	<-received
+	require.NoError(t, sub.(io.Closer).Close())
+	<-consumed
+	<-publishing
+	require.False(t, sub.HasNext())
+	require.Zero(t, hub.Len())
+}
+
+func TestHub_DropPolicies(t *testing.T) {
+	hub := NewHub[int]()
+	oldest := hub.Subscribe(2, SlowDropOldest)
+	newest := hub.Subscribe(2, SlowDropNewest)
+	unbuffered := hub.Subscribe(0, SlowDropOldest)
+
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, hub.Publish(context.Background(), i))
+	}
+	require.NoError(t, hub.Close())
+
+	require.Equal(t, []int{3, 4}, collect(oldest))
+	require.Equal(t, []int{1, 2}, collect(newest))
+	require.Empty(t, collect(unbuffered))
+}
+
+func TestHub_Disconnect(t *testing.T) {
+	hub := NewHub[int]()
+	slow := hub.Subscribe(1, SlowDisconnect)
+	fast := hub.Subscribe(10, SlowBlock)
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, hub.Publish(context.Background(), i))
+	}
+	require.Equal(t, 1, hub.Len())
+	require.Equal(t, []int{1}, collect(slow))
+
+	require.NoError(t, hub.Close())
+	require.Equal(t, []int{1, 2, 3}, collect(fast))
+}
+
+func TestHub_Block(t *testing.T) {
+	hub := NewHub[int]()
+	sub := hub.Subscribe(0, SlowBlock)
+
+	published := make(chan error)
+	go func() { published <- hub.Publish(context.Background(), 1) }()
+	require.True(t, sub.HasNext())
+	require.Equal(t, 1, sub.Next())
+	require.NoError(t, <-published)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, hub.Publish(ctx, 2), context.DeadlineExceeded)
+
+	// unsubscribing releases a blocked publisher
+	go func() { published <- hub.Publish(context.Background(), 3) }()
+	require.NoError(t, sub.(io.Closer).Close())
+	require.NoError(t, <-published)
+}
+
+func TestHub_CloseReleasesBlockedPublisher(t *testing.T) {
+	hub := NewHub[int]()
+	sub := hub.Subscribe(0, SlowBlock)
+
+	published := make(chan error)
+	go func() { published <- hub.Publish(context.Background(), 1) }()
+
+	select {
+	case <-published:
+		t.Fatal("the publisher should wait for the subscriber")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	require.NoError(t, hub.Close())
+	require.ErrorIs(t, <-published, ErrHubClosed)
+	require.False(t, sub.HasNext())
+}
+
+func TestHub_BlockedPublisherDoesNotBlockOthers(t *testing.T) {
+	hub := NewHub[int]()
+	stuck := hub.Subscribe(0, SlowBlock)
+
+	published := make(chan error)
+	go func() { published <- hub.Publish(context.Background(), 1) }()
+	time.Sleep(10 * time.Millisecond)
+
+	// subscribing and publishing don't wait for the blocked publisher
+	other := hub.Subscribe(1, SlowDropNewest)
+	require.NoError(t, stuck.(io.Closer).Close())
+	require.NoError(t, <-published)
+	require.NoError(t, hub.Publish(context.Background(), 2))
+	require.True(t, other.HasNext())
+	require.Equal(t, 2, other.Next())
+}
+
+func TestHub_ContextKeepsDelivering(t *testing.T) {
+	hub := NewHub[int]()
+	blocked := hub.Subscribe(0, SlowBlock)
+	buffered := make([]Iter[int], 5)
+	for i := range buffered {
+		buffered[i] = hub.Subscribe(1, SlowBlock)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, hub.Publish(ctx, 1), context.DeadlineExceeded)
+
+	require.NoError(t, hub.Close())
+	require.Empty(t, collect(blocked))
+	for _, sub := range buffered {
+		require.Equal(t, []int{1}, collect(sub))
+	}
+}
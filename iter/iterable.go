@@ -928,6 +928,9 @@ func (c *clonableClosableConsIter[T]) Append(i hie.Iter[T]) *clonableClosableCon
 	return next
 }
 
+// cons chains the iterators of a concat, the concat appends to its tail in place while it is being iterated.
+// It isn't a hie.List: appending to a persistent list copies all its nodes, and the nodes hold stateful iterators
+// that versions of a persistent list would share.
 type cons[T any] struct {
 	under hie.Iter[T]
 	next  *cons[T]
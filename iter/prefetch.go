@@ -0,0 +1,149 @@
+package iter
+
+import (
+	"sync"
+
+	"github.com/casualjim/hie"
+)
+
+// Prefetch reads up to n elements ahead of the consumer in a background go routine, preserving their order.
+// The go routine is started on the first call to HasNext or Next.
+// Closing the returned iterator stops the go routine and closes the source without waiting for the go routine,
+// so a source that blocks until it is closed doesn't block Close.
+// A panic raised by the source is re-raised in the consumer's go routine instead of crashing the process.
+func Prefetch[T any](iter hie.Iter[T], n int) hie.Iter[T] {
+	if n < 1 {
+		n = 1
+	}
+	return &prefetchIter[T]{
+		under: iter,
+		size:  n,
+		stop:  make(chan struct{}),
+	}
+}
+
+type prefetched[T any] struct {
+	value     T
+	panicked  bool
+	recovered any
+}
+
+type prefetchIter[T any] struct {
+	under hie.Iter[T]
+	size  int
+
+	start     sync.Once
+	source    sync.Mutex // held by the go routine while it uses the source
+	stop      chan struct{}
+	items     chan prefetched[T]
+	pending   *prefetched[T]
+	exhausted bool
+	closed    bool
+}
+
+func (p *prefetchIter[T]) run() {
+	defer close(p.items)
+	defer func() {
+		if r := recover(); r != nil {
+			select {
+			case p.items <- prefetched[T]{panicked: true, recovered: r}:
+			case <-p.stop:
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		value, ok := p.pull()
+		if !ok {
+			return
+		}
+
+		select {
+		case p.items <- prefetched[T]{value: value}:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// pull reads the next element from the source, unless the iterator was closed
+func (p *prefetchIter[T]) pull() (T, bool) {
+	p.source.Lock()
+	defer p.source.Unlock()
+
+	var zero T
+	select {
+	case <-p.stop:
+		return zero, false
+	default:
+	}
+	if !p.under.HasNext() {
+		return zero, false
+	}
+	return p.under.Next(), true
+}
+
+func (p *prefetchIter[T]) launch() {
+	p.start.Do(func() {
+		p.items = make(chan prefetched[T], p.size)
+		go p.run()
+	})
+}
+
+func (p *prefetchIter[T]) HasNext() bool {
+	if p.closed || p.exhausted {
+		return false
+	}
+	if p.pending != nil {
+		return true
+	}
+
+	p.launch()
+	item, ok := <-p.items
+	if !ok {
+		p.exhausted = true
+		return false
+	}
+	if item.panicked {
+		p.exhausted = true
+		panic(item.recovered)
+	}
+	p.pending = &item
+	return true
+}
+
+func (p *prefetchIter[T]) Next() T {
+	if p.closed {
+		panic("next called on a closed iterator")
+	}
+	if !p.HasNext() {
+		panic("iterating beyond end")
+	}
+	item := p.pending
+	p.pending = nil
+	return item.value
+}
+
+// Close stops the background go routine and closes the source.
+// When the go routine is waiting on the source, the source is closed concurrently so that it can unblock the go routine.
+func (p *prefetchIter[T]) Close() error {
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	p.pending = nil
+
+	p.start.Do(func() {}) // a closed iterator never starts prefetching
+	close(p.stop)
+	if p.source.TryLock() {
+		// the go routine isn't using the source, and won't anymore now that stop is closed
+		defer p.source.Unlock()
+	}
+	return Close(p.under)
+}
@@ -0,0 +1,130 @@
+package iter
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/casualjim/hie"
+	"github.com/stretchr/testify/require"
+)
+
+type panickingIter struct {
+	remaining int
+}
+
+func (p *panickingIter) HasNext() bool {
+	if p.remaining == 0 {
+		panic("source failed")
+	}
+	return true
+}
+
+func (p *panickingIter) Next() int {
+	p.remaining--
+	return p.remaining
+}
+
+// blockingCloseIter yields a single element and then blocks until it is closed, like a network stream
+type blockingCloseIter struct {
+	yielded bool
+	closed  chan struct{}
+	once    sync.Once
+}
+
+func (b *blockingCloseIter) HasNext() bool {
+	if !b.yielded {
+		return true
+	}
+	<-b.closed
+	return false
+}
+
+func (b *blockingCloseIter) Next() int {
+	b.yielded = true
+	return 1
+}
+
+func (b *blockingCloseIter) Close() error {
+	b.once.Do(func() { close(b.closed) })
+	return nil
+}
+
+func TestPrefetch(t *testing.T) {
+	t.Parallel()
+
+	it := Prefetch(hie.Slice(1, 2, 3, 4, 5).AsIter(), 2)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, Collect(it))
+	require.False(t, it.HasNext())
+	require.Panics(t, func() { it.Next() })
+}
+
+func TestPrefetch_Empty(t *testing.T) {
+	t.Parallel()
+
+	it := Prefetch(Empty[int](), 0)
+	require.False(t, it.HasNext())
+	require.NoError(t, Close(it))
+}
+
+func TestPrefetch_Close(t *testing.T) {
+	t.Parallel()
+
+	closes := &totalCount{}
+	src := &countingCloseIter{w: &testCloseIter{}, total: closes}
+
+	it := Prefetch[int](src, 4)
+	require.True(t, IsClosable(it))
+	require.True(t, it.HasNext())
+	require.Equal(t, 1, it.Next())
+
+	require.NoError(t, Close(it))
+	require.Equal(t, 1, closes.Total())
+	require.False(t, it.HasNext())
+	require.Panics(t, func() { it.Next() })
+
+	require.NoError(t, Close(it))
+	require.Equal(t, 1, closes.Total())
+}
+
+func TestPrefetch_CloseBeforeStart(t *testing.T) {
+	t.Parallel()
+
+	closes := &totalCount{}
+	src := &countingCloseIter{w: &testCloseIter{}, total: closes}
+
+	it := Prefetch[int](src, 4)
+	require.NoError(t, Close(it))
+	require.Equal(t, 1, closes.Total())
+	require.False(t, it.HasNext())
+}
+
+func TestPrefetch_SourcePanic(t *testing.T) {
+	t.Parallel()
+
+	it := Prefetch[int](&panickingIter{remaining: 2}, 1)
+	require.True(t, it.HasNext())
+	require.Equal(t, 1, it.Next())
+	require.True(t, it.HasNext())
+	require.Equal(t, 0, it.Next())
+	require.PanicsWithValue(t, "source failed", func() { it.HasNext() })
+	require.False(t, it.HasNext())
+}
+
+func TestPrefetch_CloseBlockedSource(t *testing.T) {
+	t.Parallel()
+
+	it := Prefetch[int](&blockingCloseIter{closed: make(chan struct{})}, 2)
+	require.True(t, it.HasNext())
+	require.Equal(t, 1, it.Next())
+
+	closed := make(chan error, 1)
+	go func() { closed <- Close(it) }()
+	select {
+	case err := <-closed:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("close blocked on the source")
+	}
+	require.False(t, it.HasNext())
+}
@@ -0,0 +1,167 @@
+package iter
+
+import (
+	"sync"
+
+	"github.com/casualjim/hie"
+)
+
+// Synchronized wraps the iterator so that it can be drained by several go routines at the same time.
+// Consumers should use TryNext, because another go routine can take the element between a call to HasNext and Next.
+func Synchronized[T any](iter hie.Iter[T]) *SyncIter[T] {
+	return &SyncIter[T]{under: iter}
+}
+
+// SyncIter is an iterator that is safe for concurrent use
+type SyncIter[T any] struct {
+	mu      sync.Mutex
+	under   hie.Iter[T]
+	pending *T
+	closed  bool
+}
+
+// TryNext atomically checks for and takes the next element, it returns false when the iterator is exhausted
+func (s *SyncIter[T]) TryNext() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.take()
+}
+
+// HasNext reports whether there is an element left, the element is reserved until any go routine calls Next
+func (s *SyncIter[T]) HasNext() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if s.pending != nil {
+		return true
+	}
+	if !s.under.HasNext() {
+		return false
+	}
+	v := s.under.Next()
+	s.pending = &v
+	return true
+}
+
+func (s *SyncIter[T]) Next() T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		panic("next called on a closed iterator")
+	}
+	v, ok := s.take()
+	if !ok {
+		panic("iterating beyond end")
+	}
+	return v
+}
+
+// Close closes the underlying iterator, only the first call has an effect
+func (s *SyncIter[T]) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.pending = nil
+	return Close(s.under)
+}
+
+func (s *SyncIter[T]) take() (T, bool) {
+	var zero T
+	if s.closed {
+		return zero, false
+	}
+	if s.pending != nil {
+		v := *s.pending
+		s.pending = nil
+		return v, true
+	}
+	if !s.under.HasNext() {
+		return zero, false
+	}
+	return s.under.Next(), true
+}
+
+// Distribute creates n iterators that pull their elements from the same source, every element is seen by exactly one of them.
+// Each returned iterator must be used by a single go routine. The source is closed once all returned iterators are closed.
+func Distribute[T any](iter hie.Iter[T], n int) []hie.Iter[T] {
+	if n < 1 {
+		panic("at least 1 iterator must be distributed")
+	}
+	shared := &distributed[T]{
+		source: Synchronized(iter),
+		open:   n,
+	}
+
+	iters := make([]hie.Iter[T], n)
+	for i := range iters {
+		iters[i] = &distributedIter[T]{shared: shared}
+	}
+	return iters
+}
+
+type distributed[T any] struct {
+	source *SyncIter[T]
+	mu     sync.Mutex
+	open   int
+}
+
+func (d *distributed[T]) release() error {
+	d.mu.Lock()
+	d.open--
+	last := d.open == 0
+	d.mu.Unlock()
+
+	if !last {
+		return nil
+	}
+	return d.source.Close()
+}
+
+type distributedIter[T any] struct {
+	shared  *distributed[T]
+	pending *T
+	closed  bool
+}
+
+func (d *distributedIter[T]) HasNext() bool {
+	if d.closed {
+		return false
+	}
+	if d.pending != nil {
+		return true
+	}
+	v, ok := d.shared.source.TryNext()
+	if !ok {
+		return false
+	}
+	d.pending = &v
+	return true
+}
+
+func (d *distributedIter[T]) Next() T {
+	if d.closed {
+		panic("next called on a closed iterator")
+	}
+	if !d.HasNext() {
+		panic("iterating beyond end")
+	}
+	v := *d.pending
+	d.pending = nil
+	return v
+}
+
+// Close releases this consumer, the last consumer to close closes the source.
+// An element that was reserved by HasNext but not taken with Next is dropped.
+func (d *distributedIter[T]) Close() error {
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	d.pending = nil
+	return d.shared.release()
+}
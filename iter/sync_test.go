@@ -0,0 +1,118 @@
+package iter
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/casualjim/hie"
+	"github.com/stretchr/testify/require"
+)
+
+func numbers(n int) []int {
+	res := make([]int, n)
+	for i := range res {
+		res[i] = i
+	}
+	return res
+}
+
+func TestSynchronized_TryNext(t *testing.T) {
+	t.Parallel()
+
+	it := Synchronized(hie.Slice(numbers(1000)...).AsIter())
+
+	var mu sync.Mutex
+	var seen []int
+	var wg sync.WaitGroup
+	wg.Add(8)
+	for i := 0; i < 8; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				v, ok := it.TryNext()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				seen = append(seen, v)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Ints(seen)
+	require.Equal(t, numbers(1000), seen)
+	_, ok := it.TryNext()
+	require.False(t, ok)
+}
+
+func TestSynchronized_Iter(t *testing.T) {
+	t.Parallel()
+
+	it := Synchronized(hie.Slice(1, 2, 3).AsIter())
+	require.True(t, it.HasNext())
+	v, ok := it.TryNext()
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+	require.Equal(t, []int{2, 3}, Collect[int](it))
+	require.Panics(t, func() { it.Next() })
+
+	closes := &totalCount{}
+	it = Synchronized[int](&countingCloseIter{w: &testCloseIter{}, total: closes})
+	require.True(t, it.HasNext())
+	require.NoError(t, it.Close())
+	require.NoError(t, it.Close())
+	require.Equal(t, 1, closes.Total())
+	require.False(t, it.HasNext())
+	require.Panics(t, func() { it.Next() })
+}
+
+func TestDistribute(t *testing.T) {
+	t.Parallel()
+
+	iters := Distribute(hie.Slice(numbers(1000)...).AsIter(), 4)
+	require.Len(t, iters, 4)
+
+	results := make([][]int, len(iters))
+	var wg sync.WaitGroup
+	wg.Add(len(iters))
+	for i, it := range iters {
+		i, it := i, it
+		go func() {
+			defer wg.Done()
+			results[i] = Collect(it)
+		}()
+	}
+	wg.Wait()
+
+	var seen []int
+	for _, r := range results {
+		require.True(t, sort.IntsAreSorted(r))
+		seen = append(seen, r...)
+	}
+	sort.Ints(seen)
+	require.Equal(t, numbers(1000), seen)
+
+	require.Panics(t, func() { Distribute(Empty[int](), 0) })
+}
+
+func TestDistribute_Close(t *testing.T) {
+	t.Parallel()
+
+	closes := &totalCount{}
+	iters := Distribute[int](&countingCloseIter{w: &testCloseIter{}, total: closes}, 2)
+
+	require.True(t, iters[0].HasNext())
+	require.Equal(t, 1, iters[0].Next())
+	require.NoError(t, Close(iters[0]))
+	require.NoError(t, Close(iters[0]))
+	require.Equal(t, 0, closes.Total())
+	require.False(t, iters[0].HasNext())
+	require.Panics(t, func() { iters[0].Next() })
+
+	require.True(t, iters[1].HasNext())
+	require.NoError(t, Close(iters[1]))
+	require.Equal(t, 1, closes.Total())
+}
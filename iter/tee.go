@@ -0,0 +1,230 @@
+package iter
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/casualjim/hie"
+)
+
+// ErrTeeBufferFull is returned by TeeIter.Err when a consumer got too far ahead of the slowest consumer
+var ErrTeeBufferFull = errors.New("tee buffer is full")
+
+// TeeOverflow decides what happens when a consumer of a tee would exceed the maximum buffer
+type TeeOverflow int
+
+const (
+	// TeeError ends the iteration of the consumer that is too far ahead, its Err method returns ErrTeeBufferFull
+	TeeError TeeOverflow = iota
+	// TeeBlock makes the consumer that is too far ahead wait until the slowest consumer catches up
+	TeeBlock
+)
+
+// TeeOptions limits the number of elements a tee buffers
+type TeeOptions struct {
+	// MaxBuffer is the maximum number of elements kept for the slower consumers, 0 means unbounded
+	MaxBuffer int
+	// Overflow decides what happens when the buffer would grow beyond MaxBuffer
+	Overflow TeeOverflow
+}
+
+// Tee splits an iterator into n iterators that each see every element of the source.
+// Only the elements between the slowest and the fastest consumer are buffered.
+// The returned iterators are safe to use from different go routines, the source is closed once all of them are closed.
+// With TeeBlock, a consumer that waits for a slower consumer in the same go routine blocks forever.
+func Tee[T any](iter hie.Iter[T], n int, options ...TeeOptions) []*TeeIter[T] {
+	if n < 1 {
+		panic("at least 1 iterator must be teed")
+	}
+	if len(options) > 1 {
+		panic("only 1 options can be specified")
+	}
+
+	shared := &tee[T]{
+		source:    iter,
+		positions: make([]int, n),
+		released:  make([]bool, n),
+		open:      n,
+	}
+	if len(options) == 1 {
+		shared.opts = options[0]
+	}
+	shared.cond = sync.NewCond(&shared.mu)
+
+	iters := make([]*TeeIter[T], n)
+	for i := range iters {
+		iters[i] = &TeeIter[T]{shared: shared, index: i}
+	}
+	return iters
+}
+
+type tee[T any] struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	source hie.Iter[T]
+	opts   TeeOptions
+
+	buffer    []T
+	base      int // the position of the first element in the buffer
+	positions []int
+	released  []bool
+	open      int
+	pulling   bool
+	exhausted bool
+}
+
+// available reports whether the consumer has an element, pulling from the source when needed, it must be called while holding mu
+func (t *tee[T]) available(i int) (bool, error) {
+	for {
+		if t.released[i] {
+			return false, nil
+		}
+		if t.positions[i] < t.base+len(t.buffer) {
+			return true, nil
+		}
+		if t.exhausted {
+			return false, nil
+		}
+		if t.pulling {
+			t.cond.Wait()
+			continue
+		}
+		if t.opts.MaxBuffer > 0 && t.positions[i]-t.slowest() >= t.opts.MaxBuffer {
+			if t.opts.Overflow == TeeBlock {
+				t.cond.Wait()
+				continue
+			}
+			return false, ErrTeeBufferFull
+		}
+		t.pull()
+	}
+}
+
+// pull reads the next element from the source without holding mu, so the other consumers can read the buffer meanwhile
+func (t *tee[T]) pull() {
+	t.pulling = true
+	t.mu.Unlock()
+
+	var value T
+	ok := false
+	defer func() {
+		t.mu.Lock()
+		t.pulling = false
+		if ok {
+			t.buffer = append(t.buffer, value)
+		} else {
+			t.exhausted = true // also when the source panicked
+		}
+		t.cond.Broadcast()
+	}()
+
+	if t.source.HasNext() {
+		value = t.source.Next()
+		ok = true
+	}
+}
+
+// slowest returns the position of the slowest consumer that is still open, it must be called while holding mu
+func (t *tee[T]) slowest() int {
+	slowest := -1
+	for i, p := range t.positions {
+		if !t.released[i] && (slowest < 0 || p < slowest) {
+			slowest = p
+		}
+	}
+	if slowest < 0 {
+		return t.base + len(t.buffer)
+	}
+	return slowest
+}
+
+// trim drops the elements every open consumer has seen, it must be called while holding mu
+func (t *tee[T]) trim() {
+	drop := t.slowest() - t.base
+	if drop <= 0 {
+		return
+	}
+	var zero T
+	for k := 0; k < drop; k++ {
+		t.buffer[k] = zero
+	}
+	t.buffer = t.buffer[drop:]
+	t.base += drop
+	t.cond.Broadcast()
+}
+
+// release stops tracking the consumer and closes the source when it was the last one, it must be called while holding mu
+func (t *tee[T]) release(i int) error {
+	if t.released[i] {
+		return nil
+	}
+	t.released[i] = true
+	t.open--
+	t.trim()
+	t.cond.Broadcast()
+	if t.open > 0 {
+		return nil
+	}
+	return Close(t.source)
+}
+
+// TeeIter is one of the iterators created by Tee
+type TeeIter[T any] struct {
+	shared *tee[T]
+	index  int
+	err    error
+	closed bool
+}
+
+func (t *TeeIter[T]) HasNext() bool {
+	t.shared.mu.Lock()
+	defer t.shared.mu.Unlock()
+	return t.hasNext()
+}
+
+func (t *TeeIter[T]) hasNext() bool {
+	if t.closed || t.err != nil {
+		return false
+	}
+	ok, err := t.shared.available(t.index)
+	if err != nil {
+		t.err = err
+		_ = t.shared.release(t.index) // a failed consumer must not hold back the others
+	}
+	return ok
+}
+
+func (t *TeeIter[T]) Next() T {
+	t.shared.mu.Lock()
+	defer t.shared.mu.Unlock()
+	if t.closed {
+		panic("next called on a closed iterator")
+	}
+	if !t.hasNext() {
+		panic("iterating beyond end")
+	}
+
+	s := t.shared
+	value := s.buffer[s.positions[t.index]-s.base]
+	s.positions[t.index]++
+	s.trim()
+	return value
+}
+
+// Err returns ErrTeeBufferFull when the iteration ended because this consumer got too far ahead
+func (t *TeeIter[T]) Err() error {
+	t.shared.mu.Lock()
+	defer t.shared.mu.Unlock()
+	return t.err
+}
+
+// Close releases this consumer, the last consumer to close closes the source
+func (t *TeeIter[T]) Close() error {
+	t.shared.mu.Lock()
+	defer t.shared.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	return t.shared.release(t.index)
+}
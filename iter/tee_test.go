@@ -0,0 +1,116 @@
+package iter
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/casualjim/hie"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 0; i < 100; i++ {
+			ch <- i
+		}
+	}()
+
+	iters := Tee(hie.Chan(ch), 3)
+	results := make([][]int, len(iters))
+	var wg sync.WaitGroup
+	wg.Add(len(iters))
+	for i, it := range iters {
+		i, it := i, it
+		go func() {
+			defer wg.Done()
+			results[i] = Collect[int](it)
+		}()
+	}
+	wg.Wait()
+
+	for i, it := range iters {
+		require.Equal(t, numbers(100), results[i])
+		require.NoError(t, it.Err())
+	}
+
+	require.Panics(t, func() { Tee(Empty[int](), 0) })
+	require.Panics(t, func() { Tee(Empty[int](), 1, TeeOptions{}, TeeOptions{}) })
+}
+
+func TestTee_BuffersTheGap(t *testing.T) {
+	t.Parallel()
+
+	iters := Tee(hie.Slice(1, 2, 3, 4).AsIter(), 2)
+	for i := 1; i <= 3; i++ {
+		require.Equal(t, i, iters[0].Next())
+	}
+	require.Len(t, iters[0].shared.buffer, 3)
+
+	require.Equal(t, 1, iters[1].Next())
+	require.Equal(t, 2, iters[1].Next())
+	require.Len(t, iters[0].shared.buffer, 1)
+
+	// a closed consumer doesn't hold on to elements
+	require.NoError(t, iters[1].Close())
+	require.Empty(t, iters[0].shared.buffer)
+	require.Equal(t, []int{4}, Collect[int](iters[0]))
+}
+
+func TestTee_MaxBufferError(t *testing.T) {
+	t.Parallel()
+
+	iters := Tee(hie.Slice(1, 2, 3, 4).AsIter(), 2, TeeOptions{MaxBuffer: 2})
+	require.Equal(t, []int{1, 2}, Collect[int](iters[0]))
+	require.ErrorIs(t, iters[0].Err(), ErrTeeBufferFull)
+	require.False(t, iters[0].HasNext())
+
+	// the failed consumer no longer limits the others
+	require.Equal(t, []int{1, 2, 3, 4}, Collect[int](iters[1]))
+	require.NoError(t, iters[1].Err())
+}
+
+func TestTee_MaxBufferBlock(t *testing.T) {
+	t.Parallel()
+
+	iters := Tee(hie.Slice(1, 2, 3).AsIter(), 2, TeeOptions{MaxBuffer: 1, Overflow: TeeBlock})
+	require.Equal(t, 1, iters[0].Next())
+
+	next := make(chan int)
+	go func() { next <- iters[0].Next() }()
+
+	select {
+	case <-next:
+		t.Fatal("the fastest consumer should wait for the slowest")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	require.Equal(t, 1, iters[1].Next())
+	require.Equal(t, 2, <-next)
+	require.Equal(t, 2, iters[1].Next())
+	require.Equal(t, 3, iters[0].Next())
+	require.Equal(t, []int{3}, Collect[int](iters[1]))
+	require.False(t, iters[0].HasNext())
+}
+
+func TestTee_Close(t *testing.T) {
+	t.Parallel()
+
+	closes := &totalCount{}
+	iters := Tee[int](&countingCloseIter{w: &testCloseIter{}, total: closes}, 2)
+
+	require.True(t, iters[0].HasNext())
+	require.Equal(t, 1, iters[0].Next())
+	require.NoError(t, iters[0].Close())
+	require.NoError(t, iters[0].Close())
+	require.Equal(t, 0, closes.Total())
+	require.False(t, iters[0].HasNext())
+	require.Panics(t, func() { iters[0].Next() })
+
+	require.NoError(t, Close[int](iters[1]))
+	require.Equal(t, 1, closes.Total())
+}
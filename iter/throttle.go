@@ -0,0 +1,131 @@
+package iter
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/casualjim/hie"
+	"github.com/casualjim/hie/clock"
+)
+
+// Throttle limits the rate at which elements are pulled from the iterator with a token bucket.
+// The bucket holds up to burst tokens and is refilled with rate tokens per second,
+// HasNext consults the source first and then blocks until a token is available,
+// so detecting the end of the source doesn't cost a token.
+// An optional clock can be provided to control the passing of time, it defaults to the wall clock.
+func Throttle[T any](iter hie.Iter[T], rate float64, burst int, clk ...clock.Clock) hie.Iter[T] {
+	return ThrottleContext(context.Background(), iter, rate, burst, clk...)
+}
+
+// ThrottleContext limits the rate at which elements are pulled from the iterator with a token bucket.
+// When the context is done while waiting for a token the iterator ends.
+func ThrottleContext[T any](ctx context.Context, iter hie.Iter[T], rate float64, burst int, clk ...clock.Clock) hie.Iter[T] {
+	if rate <= 0 {
+		panic("the rate must be positive")
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	c := clock.Or(clk...)
+	ti := throttleIter[T]{
+		ctx:   ctx,
+		under: iter,
+		bucket: tokenBucket{
+			clock:  c,
+			rate:   rate,
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   c.Now(),
+		},
+	}
+
+	if IsClosable(iter) {
+		return &closableThrottleIter[T]{
+			throttleIter: ti,
+		}
+	}
+	return &ti
+}
+
+type tokenBucket struct {
+	clock  clock.Clock
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(ctx context.Context) error {
+	for {
+		now := b.clock.Now()
+		if elapsed := now.Sub(b.last); elapsed > 0 {
+			b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+			b.last = now
+		}
+		if b.tokens >= 1 {
+			b.tokens--
+			return nil
+		}
+
+		wait := time.Duration(math.Ceil((1 - b.tokens) / b.rate * float64(time.Second)))
+		if err := clock.Sleep(ctx, b.clock, wait); err != nil {
+			return err
+		}
+	}
+}
+
+type throttleIter[T any] struct {
+	ctx      context.Context
+	under    hie.Iter[T]
+	bucket   tokenBucket
+	reserved bool
+	done     bool
+}
+
+func (t *throttleIter[T]) HasNext() bool {
+	if t.done {
+		return false
+	}
+	if t.reserved {
+		return true
+	}
+	if !t.under.HasNext() {
+		return false
+	}
+	if err := t.bucket.take(t.ctx); err != nil {
+		t.done = true
+		return false
+	}
+	t.reserved = true
+	return true
+}
+
+func (t *throttleIter[T]) Next() T {
+	if !t.HasNext() {
+		panic("iterating beyond end")
+	}
+	t.reserved = false
+	return t.under.Next()
+}
+
+type closableThrottleIter[T any] struct {
+	throttleIter[T]
+	closed bool
+}
+
+func (c *closableThrottleIter[T]) HasNext() bool {
+	return !c.closed && c.throttleIter.HasNext()
+}
+
+func (c *closableThrottleIter[T]) Next() T {
+	if c.closed {
+		panic("next called on a closed iterator")
+	}
+	return c.throttleIter.Next()
+}
+
+func (c *closableThrottleIter[T]) Close() error {
+	c.closed = true
+	return Close(c.under)
+}
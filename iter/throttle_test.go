@@ -0,0 +1,77 @@
+package iter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/casualjim/hie"
+	"github.com/casualjim/hie/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottle(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	it := Throttle(hie.Slice(1, 2, 3, 4).AsIter(), 2, 2, clk)
+
+	// the burst is available right away
+	require.True(t, it.HasNext())
+	require.Equal(t, 1, it.Next())
+	require.True(t, it.HasNext())
+	require.Equal(t, 2, it.Next())
+
+	results := make(chan int)
+	go func() {
+		for it.HasNext() {
+			results <- it.Next()
+		}
+		close(results)
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(500 * time.Millisecond)
+	require.Equal(t, 3, <-results)
+
+	clk.BlockUntil(1)
+	clk.Advance(500 * time.Millisecond)
+	require.Equal(t, 4, <-results)
+
+	// the end of the source is detected without waiting for a token
+	_, open := <-results
+	require.False(t, open)
+}
+
+func TestThrottleContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clk := clock.NewManual(time.Now())
+	it := ThrottleContext(ctx, hie.Slice(1, 2, 3).AsIter(), 1, 1, clk)
+
+	require.True(t, it.HasNext())
+	require.Equal(t, 1, it.Next())
+
+	done := make(chan bool)
+	go func() { done <- it.HasNext() }()
+	clk.BlockUntil(1)
+	cancel()
+	require.False(t, <-done)
+	require.False(t, it.HasNext())
+}
+
+func TestThrottle_Close(t *testing.T) {
+	t.Parallel()
+
+	closes := &totalCount{}
+	it := Throttle[int](&countingCloseIter{w: &testCloseIter{}, total: closes}, 10, 1)
+	require.True(t, IsClosable(it))
+	require.True(t, it.HasNext())
+	require.Equal(t, 1, it.Next())
+	require.NoError(t, Close(it))
+	require.Equal(t, 1, closes.Total())
+	require.False(t, it.HasNext())
+
+	require.False(t, IsClosable(Throttle(hie.Slice(1).AsIter(), 1, 1)))
+}
@@ -0,0 +1,117 @@
+package hie
+
+// ListOf creates a persistent list with the provided values
+func ListOf[T any](values ...T) List[T] {
+	var l List[T]
+	for i := len(values) - 1; i >= 0; i-- {
+		l = l.Prepend(values[i])
+	}
+	return l
+}
+
+// List is an immutable singly linked list, every modification returns a new list that shares its nodes with the original.
+// Lists can be shared between go routines without copying, the zero value is an empty list.
+type List[T any] struct {
+	head *listNode[T]
+	size int
+}
+
+type listNode[T any] struct {
+	value T
+	next  *listNode[T]
+}
+
+// Len returns the number of elements in the list
+func (l List[T]) Len() int { return l.size }
+
+// IsEmpty returns true when the list has no elements
+func (l List[T]) IsEmpty() bool { return l.size == 0 }
+
+// Head returns the first element, the second return value is false when the list is empty
+func (l List[T]) Head() (T, bool) {
+	if l.head == nil {
+		var zero T
+		return zero, false
+	}
+	return l.head.value, true
+}
+
+// Tail returns the list without its first element, it shares all its nodes with this list
+func (l List[T]) Tail() List[T] {
+	if l.head == nil {
+		return l
+	}
+	return List[T]{head: l.head.next, size: l.size - 1}
+}
+
+// Get returns the element at the index, it panics when the index is out of range
+func (l List[T]) Get(index int) T {
+	return l.node(index).value
+}
+
+// Prepend returns a new list with the value in front of this list, in constant time
+func (l List[T]) Prepend(value T) List[T] {
+	return List[T]{head: &listNode[T]{value: value, next: l.head}, size: l.size + 1}
+}
+
+// Append returns a new list with the value at the end, this copies all the nodes of the list
+func (l List[T]) Append(value T) List[T] {
+	return l.rebuild(l.size, &listNode[T]{value: value}, l.size+1)
+}
+
+// Set returns a new list with the value at the index, the nodes after the index are shared with this list
+func (l List[T]) Set(index int, value T) List[T] {
+	n := l.node(index)
+	return l.rebuild(index, &listNode[T]{value: value, next: n.next}, l.size)
+}
+
+// AsIter returns a clonable iterator over the elements of the list
+func (l List[T]) AsIter() Iter[T] {
+	return &listIter[T]{cur: l.head}
+}
+
+func (l List[T]) node(index int) *listNode[T] {
+	if index < 0 || index >= l.size {
+		panic("index out of range")
+	}
+	n := l.head
+	for i := 0; i < index; i++ {
+		n = n.next
+	}
+	return n
+}
+
+// rebuild copies the first count nodes of the list in front of rest, which makes a list of size elements
+func (l List[T]) rebuild(count int, rest *listNode[T], size int) List[T] {
+	res := &listNode[T]{}
+	prev := res
+	n := l.head
+	for i := 0; i < count; i++ {
+		prev.next = &listNode[T]{value: n.value}
+		prev = prev.next
+		n = n.next
+	}
+	prev.next = rest
+	return List[T]{head: res.next, size: size}
+}
+
+type listIter[T any] struct {
+	cur *listNode[T]
+}
+
+func (l *listIter[T]) HasNext() bool {
+	return l.cur != nil
+}
+
+func (l *listIter[T]) Next() T {
+	if l.cur == nil {
+		panic("iterating beyond end")
+	}
+	v := l.cur.value
+	l.cur = l.cur.next
+	return v
+}
+
+func (l *listIter[T]) Clone() Iter[T] {
+	return &listIter[T]{cur: l.cur}
+}
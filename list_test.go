@@ -0,0 +1,51 @@
+package hie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	var empty List[int]
+	require.True(t, empty.IsEmpty())
+	_, ok := empty.Head()
+	require.False(t, ok)
+	require.Equal(t, empty, empty.Tail())
+	require.Empty(t, collect(empty.AsIter()))
+
+	l := ListOf(1, 2, 3)
+	require.Equal(t, 3, l.Len())
+	head, ok := l.Head()
+	require.True(t, ok)
+	require.Equal(t, 1, head)
+	require.Equal(t, []int{2, 3}, collect(l.Tail().AsIter()))
+	require.Equal(t, 3, l.Get(2))
+	require.Panics(t, func() { l.Get(3) })
+	require.Panics(t, func() { l.Set(-1, 0) })
+
+	prepended := l.Prepend(0)
+	appended := l.Append(4)
+	set := l.Set(1, 20)
+	require.Equal(t, []int{0, 1, 2, 3}, collect(prepended.AsIter()))
+	require.Equal(t, 4, prepended.Len())
+	require.Equal(t, []int{1, 2, 3, 4}, collect(appended.AsIter()))
+	require.Equal(t, 4, appended.Len())
+	require.Equal(t, []int{1, 20, 3}, collect(set.AsIter()))
+	require.Equal(t, 3, set.Len())
+	require.Equal(t, []int{1, 2, 3}, collect(l.AsIter()))
+
+	// the structure is shared between versions
+	require.Same(t, l.head, prepended.head.next)
+	require.Same(t, l.head.next.next, set.head.next.next)
+}
+
+func TestList_AsIterClone(t *testing.T) {
+	it := ListOf("a", "b", "c").AsIter()
+	require.Equal(t, "a", it.Next())
+
+	cl := it.(ClonableIter[string]).Clone()
+	require.Equal(t, []string{"b", "c"}, collect(it))
+	require.Equal(t, []string{"b", "c"}, collect(cl))
+	require.Panics(t, func() { it.Next() })
+}
@@ -0,0 +1,155 @@
+package hie
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextLocker is a sync.Locker whose lock can be acquired with a deadline or without waiting
+type ContextLocker interface {
+	sync.Locker
+	LockContext(ctx context.Context) error
+	TryLock() bool
+}
+
+// NewRWLock creates a reader/writer lock whose waiters can give up when their context is done
+func NewRWLock() *RWLock {
+	return &RWLock{}
+}
+
+// RWLock is a reader/writer lock, like sync.RWMutex, that also supports acquiring it with a context.
+// Waiters block on a channel that is closed whenever the lock is released, so there is no busy waiting.
+// Writers are preferred: once a writer waits, new readers wait until it got the lock.
+type RWLock struct {
+	mu             sync.Mutex
+	writer         bool
+	readers        int
+	waitingWriters int
+	released       chan struct{}
+}
+
+func (l *RWLock) Lock() {
+	_ = l.LockContext(context.Background())
+}
+
+func (l *RWLock) Unlock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.writer {
+		panic("unlock of unlocked RWLock")
+	}
+	l.writer = false
+	l.notify()
+}
+
+func (l *RWLock) RLock() {
+	_ = l.RLockContext(context.Background())
+}
+
+func (l *RWLock) RUnlock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.readers == 0 {
+		panic("runlock of unlocked RWLock")
+	}
+	l.readers--
+	if l.readers == 0 {
+		l.notify()
+	}
+}
+
+// TryLock acquires the exclusive lock when it is available right away
+func (l *RWLock) TryLock() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tryLock()
+}
+
+// TryRLock acquires the shared lock when it is available right away
+func (l *RWLock) TryRLock() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tryRLock()
+}
+
+// LockContext acquires the exclusive lock, it returns the context error when the context is done first
+func (l *RWLock) LockContext(ctx context.Context) error {
+	l.mu.Lock()
+	if l.tryLock() {
+		l.mu.Unlock()
+		return nil
+	}
+	l.waitingWriters++
+	for {
+		released := l.wait()
+		l.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			l.mu.Lock()
+			l.waitingWriters--
+			l.notify() // readers that were held back by this writer can proceed
+			l.mu.Unlock()
+			return ctx.Err()
+		}
+
+		l.mu.Lock()
+		if l.tryLock() {
+			l.waitingWriters--
+			l.mu.Unlock()
+			return nil
+		}
+	}
+}
+
+// RLockContext acquires the shared lock, it returns the context error when the context is done first
+func (l *RWLock) RLockContext(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.tryRLock() {
+			l.mu.Unlock()
+			return nil
+		}
+		released := l.wait()
+		l.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *RWLock) tryLock() bool {
+	if l.writer || l.readers > 0 {
+		return false
+	}
+	l.writer = true
+	return true
+}
+
+func (l *RWLock) tryRLock() bool {
+	if l.writer || l.waitingWriters > 0 {
+		return false
+	}
+	l.readers++
+	return true
+}
+
+// wait returns the channel that is closed on the next release, it must be called while holding mu
+func (l *RWLock) wait() <-chan struct{} {
+	if l.released == nil {
+		l.released = make(chan struct{})
+	}
+	return l.released
+}
+
+// notify wakes up the waiters, it must be called while holding mu
+func (l *RWLock) notify() {
+	if l.released != nil {
+		close(l.released)
+		l.released = nil
+	}
+}
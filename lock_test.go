@@ -0,0 +1,66 @@
+package hie
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRWLock_TryLock(t *testing.T) {
+	l := NewRWLock()
+	require.True(t, l.TryLock())
+	require.False(t, l.TryLock())
+	require.False(t, l.TryRLock())
+	l.Unlock()
+
+	require.True(t, l.TryRLock())
+	require.True(t, l.TryRLock())
+	require.False(t, l.TryLock())
+	l.RUnlock()
+	l.RUnlock()
+	require.True(t, l.TryLock())
+	l.Unlock()
+
+	require.Panics(t, l.Unlock)
+	require.Panics(t, l.RUnlock)
+}
+
+func TestRWLock_LockContext(t *testing.T) {
+	l := NewRWLock()
+	l.Lock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, l.LockContext(ctx), context.DeadlineExceeded)
+	require.ErrorIs(t, l.RLockContext(ctx), context.DeadlineExceeded)
+
+	acquired := make(chan struct{})
+	go func() {
+		l.Lock()
+		close(acquired)
+	}()
+	l.Unlock()
+	<-acquired
+	l.Unlock()
+}
+
+func TestRWLock_WritersArePreferred(t *testing.T) {
+	l := NewRWLock()
+	l.RLock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	writer := make(chan error)
+	go func() { writer <- l.LockContext(ctx) }()
+
+	require.Eventually(t, func() bool { return !l.TryRLock() }, time.Second, time.Millisecond)
+
+	// a writer that gives up lets the readers in again
+	cancel()
+	require.ErrorIs(t, <-writer, context.Canceled)
+	require.True(t, l.TryRLock())
+	l.RUnlock()
+	l.RUnlock()
+	require.True(t, l.TryLock())
+}
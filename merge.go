@@ -0,0 +1,156 @@
+package hie
+
+import (
+	"context"
+	"sync"
+)
+
+// Tagged is a value received by MergeChansTagged together with the index of the channel it came from
+type Tagged[T any] struct {
+	Source int
+	Value  T
+}
+
+// MergeChans creates an iterator that yields the values of all channels in the order they arrive.
+// The iterator ends when all channels are closed or the context is done.
+// The channels are read by go routines that start on the first call to HasNext, closing the iterator stops them.
+func MergeChans[T any](ctx context.Context, chans ...<-chan T) Iter[T] {
+	return &mergeIter[T]{
+		tagged: newTaggedMerge(ctx, chans),
+	}
+}
+
+// MergeChansTagged is like MergeChans but tags each value with the index of the channel it was received from
+func MergeChansTagged[T any](ctx context.Context, chans ...<-chan T) Iter[Tagged[T]] {
+	return newTaggedMerge(ctx, chans)
+}
+
+func newTaggedMerge[T any](ctx context.Context, chans []<-chan T) *taggedMergeIter[T] {
+	return &taggedMergeIter[T]{
+		ctx:   ctx,
+		chans: chans,
+		stop:  make(chan struct{}),
+	}
+}
+
+type taggedMergeIter[T any] struct {
+	ctx   context.Context
+	chans []<-chan T
+
+	start   sync.Once
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	out     chan Tagged[T]
+	pending *Tagged[T]
+	done    bool
+	closed  bool
+}
+
+func (m *taggedMergeIter[T]) launch() {
+	m.start.Do(func() {
+		m.out = make(chan Tagged[T])
+		for i, ch := range m.chans {
+			if ch == nil {
+				continue
+			}
+			m.wg.Add(1)
+			go m.forward(i, ch)
+		}
+		go func() {
+			m.wg.Wait()
+			close(m.out)
+		}()
+	})
+}
+
+func (m *taggedMergeIter[T]) forward(source int, ch <-chan T) {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-m.ctx.Done():
+			return
+		case val, ok := <-ch:
+			if !ok {
+				return
+			}
+			select {
+			case m.out <- Tagged[T]{Source: source, Value: val}:
+			case <-m.stop:
+				return
+			case <-m.ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (m *taggedMergeIter[T]) HasNext() bool {
+	if m.closed || m.done {
+		return false
+	}
+	if m.pending != nil {
+		return true
+	}
+
+	m.launch()
+	if m.ctx.Err() != nil {
+		m.done = true
+		return false
+	}
+	select {
+	case <-m.ctx.Done():
+		m.done = true
+		return false
+	case val, ok := <-m.out:
+		if !ok {
+			m.done = true
+			return false
+		}
+		m.pending = &val
+		return true
+	}
+}
+
+func (m *taggedMergeIter[T]) Next() Tagged[T] {
+	if m.closed {
+		panic("next called on a closed iterator")
+	}
+	if !m.HasNext() {
+		panic("iterating beyond end")
+	}
+	val := m.pending
+	m.pending = nil
+	return *val
+}
+
+// Close stops the go routines that read the channels and waits for them to exit, the channels themselves are not closed
+func (m *taggedMergeIter[T]) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	m.pending = nil
+
+	m.start.Do(func() {}) // a closed iterator never starts reading
+	close(m.stop)
+	m.wg.Wait()
+	return nil
+}
+
+type mergeIter[T any] struct {
+	tagged *taggedMergeIter[T]
+}
+
+func (m *mergeIter[T]) HasNext() bool {
+	return m.tagged.HasNext()
+}
+
+func (m *mergeIter[T]) Next() T {
+	return m.tagged.Next().Value
+}
+
+func (m *mergeIter[T]) Close() error {
+	return m.tagged.Close()
+}
@@ -0,0 +1,83 @@
+package hie
+
+import (
+	"context"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sendAll[T any](values ...T) <-chan T {
+	ch := make(chan T, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	close(ch)
+	return ch
+}
+
+func TestMergeChans(t *testing.T) {
+	it := MergeChans(context.Background(), sendAll(1, 2, 3), nil, sendAll(4, 5), sendAll[int]())
+
+	var values []int
+	for it.HasNext() {
+		values = append(values, it.Next())
+	}
+	sort.Ints(values)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, values)
+	require.False(t, it.HasNext())
+	require.Panics(t, func() { it.Next() })
+}
+
+func TestMergeChansTagged(t *testing.T) {
+	it := MergeChansTagged(context.Background(), sendAll("a", "b"), sendAll("c"))
+
+	bySource := map[int][]string{}
+	for it.HasNext() {
+		v := it.Next()
+		bySource[v.Source] = append(bySource[v.Source], v.Value)
+	}
+	require.Equal(t, map[int][]string{0: {"a", "b"}, 1: {"c"}}, bySource)
+}
+
+func TestMergeChans_YieldsAsSoonAsAnyDelivers(t *testing.T) {
+	slow := make(chan int)
+	fast := make(chan int)
+	it := MergeChansTagged(context.Background(), slow, fast)
+
+	go func() { fast <- 1 }()
+	require.True(t, it.HasNext())
+	require.Equal(t, Tagged[int]{Source: 1, Value: 1}, it.Next())
+	require.NoError(t, it.(io.Closer).Close())
+}
+
+func TestMergeChans_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+	it := MergeChans(ctx, ch)
+
+	go func() { ch <- 1 }()
+	require.True(t, it.HasNext())
+	require.Equal(t, 1, it.Next())
+
+	cancel()
+	require.False(t, it.HasNext())
+	require.NoError(t, it.(io.Closer).Close())
+}
+
+func TestMergeChans_Close(t *testing.T) {
+	ch := make(chan int)
+	it := MergeChans(context.Background(), ch)
+	require.NoError(t, it.(io.Closer).Close())
+	require.NoError(t, it.(io.Closer).Close())
+	require.False(t, it.HasNext())
+	require.Panics(t, func() { it.Next() })
+
+	it = MergeChans(context.Background(), ch)
+	go func() { ch <- 1 }()
+	require.True(t, it.HasNext())
+	require.NoError(t, it.(io.Closer).Close()) // the forwarding go routine blocked on the channel exits
+	require.False(t, it.HasNext())
+}
@@ -0,0 +1,235 @@
+package hie
+
+// NewListMultiMap creates a MultiMap that keeps every value of a key in insertion order, duplicates included
+func NewListMultiMap[K, V comparable]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{}
+}
+
+// NewSetMultiMap creates a MultiMap that keeps the distinct values of a key
+func NewSetMultiMap[K, V comparable]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{unique: true}
+}
+
+// MultiMap maps a key to many values, which are kept in a list or in a set bucket per key.
+// The zero value is an empty multimap with list buckets.
+type MultiMap[K, V comparable] struct {
+	buckets map[K]*multiMapBucket[V]
+	unique  bool
+	size    int
+}
+
+type multiMapBucket[V comparable] struct {
+	list []V
+	set  *Set[V]
+}
+
+func (b *multiMapBucket[V]) len() int {
+	if b.set != nil {
+		return b.set.Len()
+	}
+	return len(b.list)
+}
+
+func (b *multiMapBucket[V]) values() []V {
+	if b.set != nil {
+		return collect(b.set.AsIter())
+	}
+	return append([]V(nil), b.list...)
+}
+
+// Put adds the value to the key, it returns false when the value was already there in a set bucket
+func (m *MultiMap[K, V]) Put(key K, value V) bool {
+	if m.buckets == nil {
+		m.buckets = make(map[K]*multiMapBucket[V])
+	}
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &multiMapBucket[V]{}
+		if m.unique {
+			b.set = NewSet[V]()
+		}
+		m.buckets[key] = b
+	}
+
+	if b.set != nil {
+		if b.set.Has(value) {
+			return false
+		}
+		b.set.Add(value)
+	} else {
+		b.list = append(b.list, value)
+	}
+	m.size++
+	return true
+}
+
+// PutAll adds all the values to the key
+func (m *MultiMap[K, V]) PutAll(key K, values ...V) {
+	for _, v := range values {
+		m.Put(key, v)
+	}
+}
+
+// Get returns a clonable iterator over a snapshot of the values of the key
+func (m *MultiMap[K, V]) Get(key K) Iter[V] {
+	b, ok := m.buckets[key]
+	if !ok {
+		return Slice[V]().AsIter()
+	}
+	return Slice(b.values()...).AsIter()
+}
+
+// Has returns true when the key has at least one value
+func (m *MultiMap[K, V]) Has(key K) bool {
+	_, ok := m.buckets[key]
+	return ok
+}
+
+// HasEntry returns true when the value is one of the values of the key
+func (m *MultiMap[K, V]) HasEntry(key K, value V) bool {
+	return m.Count(key, value) > 0
+}
+
+// Count returns how many times the value occurs for the key
+func (m *MultiMap[K, V]) Count(key K, value V) int {
+	b, ok := m.buckets[key]
+	if !ok {
+		return 0
+	}
+	if b.set != nil {
+		if b.set.Has(value) {
+			return 1
+		}
+		return 0
+	}
+	count := 0
+	for _, v := range b.list {
+		if v == value {
+			count++
+		}
+	}
+	return count
+}
+
+// Remove removes one occurrence of the value from the key, it returns false when the value was not there
+func (m *MultiMap[K, V]) Remove(key K, value V) bool {
+	b, ok := m.buckets[key]
+	if !ok {
+		return false
+	}
+
+	removed := false
+	if b.set != nil {
+		removed = b.set.Has(value)
+		b.set.Remove(value)
+	} else {
+		for i, v := range b.list {
+			if v == value {
+				b.list = append(b.list[:i], b.list[i+1:]...)
+				removed = true
+				break
+			}
+		}
+	}
+	if !removed {
+		return false
+	}
+	m.size--
+	if b.len() == 0 {
+		delete(m.buckets, key)
+	}
+	return true
+}
+
+// RemoveAll removes the key with all its values and returns how many values were removed
+func (m *MultiMap[K, V]) RemoveAll(key K) int {
+	b, ok := m.buckets[key]
+	if !ok {
+		return 0
+	}
+	delete(m.buckets, key)
+	m.size -= b.len()
+	return b.len()
+}
+
+// Len returns the number of values over all keys
+func (m *MultiMap[K, V]) Len() int {
+	return m.size
+}
+
+// KeyLen returns the number of keys
+func (m *MultiMap[K, V]) KeyLen() int {
+	return len(m.buckets)
+}
+
+// Keys returns a clonable iterator over a snapshot of the keys, in no particular order
+func (m *MultiMap[K, V]) Keys() Iter[K] {
+	keys := make([]K, 0, len(m.buckets))
+	for k := range m.buckets {
+		keys = append(keys, k)
+	}
+	return Slice(keys...).AsIter()
+}
+
+// AsIter returns a clonable iterator over a snapshot of all the key value pairs
+func (m *MultiMap[K, V]) AsIter() Iter[Entry[K, V]] {
+	entries := make([]Entry[K, V], 0, m.size)
+	for k, b := range m.buckets {
+		for _, v := range b.values() {
+			entries = append(entries, Entry[K, V]{Key: k, Value: v})
+		}
+	}
+	return Slice(entries...).AsIter()
+}
+
+// Union returns a new multimap in which every value of a key occurs as often as in the multimap where it occurs most
+func (m *MultiMap[K, V]) Union(other *MultiMap[K, V]) *MultiMap[K, V] {
+	return m.combine(other, (*Bag[V]).Union)
+}
+
+// Intersect returns a new multimap in which every value of a key occurs as often as in the multimap where it occurs least
+func (m *MultiMap[K, V]) Intersect(other *MultiMap[K, V]) *MultiMap[K, V] {
+	return m.combine(other, (*Bag[V]).Intersect)
+}
+
+// Difference returns a new multimap with the occurrences of the values in the other multimap taken away
+func (m *MultiMap[K, V]) Difference(other *MultiMap[K, V]) *MultiMap[K, V] {
+	return m.combine(other, (*Bag[V]).Difference)
+}
+
+// combine computes the multiplicities of each key with the bag operation,
+// the values of this multimap come first in the result, followed by the additional values of the other multimap
+func (m *MultiMap[K, V]) combine(other *MultiMap[K, V], op func(*Bag[V], *Bag[V]) *Bag[V]) *MultiMap[K, V] {
+	res := &MultiMap[K, V]{unique: m.unique}
+	keys := NewSet[K]()
+	for k := range m.buckets {
+		keys.Add(k)
+	}
+	for k := range other.buckets {
+		keys.Add(k)
+	}
+
+	for k := range keys.items {
+		mine, theirs := m.values(k), other.values(k)
+		want := op(NewBag(mine...), NewBag(theirs...))
+		if want.Len() == 0 {
+			continue
+		}
+		added := &Bag[V]{}
+		for _, v := range append(mine, theirs...) {
+			if added.Count(v) < want.Count(v) {
+				added.Add(v)
+				res.Put(k, v)
+			}
+		}
+	}
+	return res
+}
+
+func (m *MultiMap[K, V]) values(key K) []V {
+	b, ok := m.buckets[key]
+	if !ok {
+		return nil
+	}
+	return b.values()
+}
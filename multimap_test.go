@@ -0,0 +1,90 @@
+package hie
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiMap_List(t *testing.T) {
+	var m MultiMap[string, int]
+	require.Empty(t, collect(m.Get("a")))
+	require.False(t, m.Remove("a", 1))
+
+	m.PutAll("a", 1, 2, 1)
+	require.True(t, m.Put("b", 3))
+	require.Equal(t, 4, m.Len())
+	require.Equal(t, 2, m.KeyLen())
+	require.Equal(t, []int{1, 2, 1}, collect(m.Get("a")))
+	require.Equal(t, 2, m.Count("a", 1))
+	require.True(t, m.HasEntry("b", 3))
+	require.False(t, m.HasEntry("b", 1))
+
+	keys := collect(m.Keys())
+	sort.Strings(keys)
+	require.Equal(t, []string{"a", "b"}, keys)
+	require.Len(t, collect(m.AsIter()), 4)
+
+	require.True(t, m.Remove("a", 1))
+	require.Equal(t, []int{2, 1}, collect(m.Get("a")))
+	require.False(t, m.Remove("a", 5))
+	require.True(t, m.Remove("b", 3))
+	require.False(t, m.Has("b"))
+	require.Equal(t, 2, m.RemoveAll("a"))
+	require.Zero(t, m.RemoveAll("a"))
+	require.Zero(t, m.Len())
+	require.Zero(t, m.KeyLen())
+}
+
+func TestMultiMap_Set(t *testing.T) {
+	m := NewSetMultiMap[string, int]()
+	require.True(t, m.Put("a", 1))
+	require.False(t, m.Put("a", 1))
+	m.PutAll("a", 2, 2)
+	require.Equal(t, 2, m.Len())
+	require.Equal(t, 1, m.Count("a", 1))
+
+	values := collect(m.Get("a"))
+	sort.Ints(values)
+	require.Equal(t, []int{1, 2}, values)
+
+	require.True(t, m.Remove("a", 1))
+	require.False(t, m.Remove("a", 1))
+	require.True(t, m.Remove("a", 2))
+	require.False(t, m.Has("a"))
+	require.Zero(t, m.Len())
+}
+
+func TestMultiMap_Algebra(t *testing.T) {
+	a := NewListMultiMap[string, int]()
+	a.PutAll("k", 1, 1, 2)
+	a.PutAll("only-a", 5)
+	b := NewListMultiMap[string, int]()
+	b.PutAll("k", 3, 1, 2, 2)
+	b.PutAll("only-b", 6)
+
+	union := a.Union(b)
+	require.Equal(t, []int{1, 1, 2, 3, 2}, collect(union.Get("k")))
+	require.Equal(t, []int{5}, collect(union.Get("only-a")))
+	require.Equal(t, []int{6}, collect(union.Get("only-b")))
+	require.Equal(t, 7, union.Len())
+
+	intersect := a.Intersect(b)
+	require.Equal(t, []int{1, 2}, collect(intersect.Get("k")))
+	require.Equal(t, 1, intersect.KeyLen())
+
+	diff := a.Difference(b)
+	require.Equal(t, []int{1}, collect(diff.Get("k")))
+	require.Equal(t, []int{5}, collect(diff.Get("only-a")))
+	require.False(t, diff.Has("only-b"))
+
+	sets := NewSetMultiMap[string, int]()
+	sets.PutAll("k", 1, 4)
+	// set buckets keep a single occurrence of each value
+	union = sets.Union(a)
+	values := collect(union.Get("k"))
+	sort.Ints(values)
+	require.Equal(t, []int{1, 2, 4}, values)
+	require.Equal(t, []int{4}, collect(sets.Difference(a).Get("k")))
+}
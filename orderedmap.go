@@ -0,0 +1,297 @@
+package hie
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Entry is a key with its value
+type Entry[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// OrderedMapOptions configures an OrderedMap
+type OrderedMapOptions struct {
+	// AccessOrder moves an entry to the end whenever it is read or written, which makes the oldest entry the least recently used one
+	AccessOrder bool
+}
+
+// NewOrderedMap creates an empty OrderedMap, by default it keeps the entries in insertion order
+func NewOrderedMap[K comparable, V any](options ...OrderedMapOptions) *OrderedMap[K, V] {
+	if len(options) > 1 {
+		panic("only 1 options can be specified")
+	}
+	m := &OrderedMap[K, V]{}
+	if len(options) == 1 {
+		m.accessOrder = options[0].AccessOrder
+	}
+	return m
+}
+
+// OrderedMap is a map that remembers the order of its entries, Get, Put and Delete take constant time.
+// Updating the value of a key keeps its position, unless the map is in access order.
+// The zero value is an empty map in insertion order.
+type OrderedMap[K comparable, V any] struct {
+	items       map[K]*orderedEntry[K, V]
+	head, tail  *orderedEntry[K, V]
+	accessOrder bool
+}
+
+type orderedEntry[K comparable, V any] struct {
+	Entry[K, V]
+	prev, next *orderedEntry[K, V]
+}
+
+// Len returns the number of entries
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.items)
+}
+
+// Has returns true when the map contains the key, it doesn't change the access order
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.items[key]
+	return ok
+}
+
+// Get returns the value for the key, in access order the entry becomes the most recently used one
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	e, ok := m.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if m.accessOrder {
+		m.moveToBack(e)
+	}
+	return e.Value, true
+}
+
+// Peek returns the value for the key without changing the access order
+func (m *OrderedMap[K, V]) Peek(key K) (V, bool) {
+	e, ok := m.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return e.Value, true
+}
+
+// Put sets the value for the key, a new key is added at the end
+func (m *OrderedMap[K, V]) Put(key K, value V) {
+	if e, ok := m.items[key]; ok {
+		e.Value = value
+		if m.accessOrder {
+			m.moveToBack(e)
+		}
+		return
+	}
+
+	if m.items == nil {
+		m.items = make(map[K]*orderedEntry[K, V])
+	}
+	e := &orderedEntry[K, V]{Entry: Entry[K, V]{Key: key, Value: value}}
+	m.items[key] = e
+	m.pushBack(e)
+}
+
+// Delete removes the key, it returns false when the key was not in the map
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	e, ok := m.items[key]
+	if !ok {
+		return false
+	}
+	delete(m.items, key)
+	m.unlink(e)
+	return true
+}
+
+// Oldest returns the first entry, which is the least recently used one in access order
+func (m *OrderedMap[K, V]) Oldest() (Entry[K, V], bool) {
+	if m.head == nil {
+		return Entry[K, V]{}, false
+	}
+	return m.head.Entry, true
+}
+
+// RemoveOldest removes and returns the first entry
+func (m *OrderedMap[K, V]) RemoveOldest() (Entry[K, V], bool) {
+	e, ok := m.Oldest()
+	if ok {
+		m.Delete(e.Key)
+	}
+	return e, ok
+}
+
+// Keys returns a clonable iterator over a snapshot of the keys in order
+func (m *OrderedMap[K, V]) Keys() Iter[K] {
+	keys := make([]K, 0, len(m.items))
+	for e := m.head; e != nil; e = e.next {
+		keys = append(keys, e.Key)
+	}
+	return Slice(keys...).AsIter()
+}
+
+// Values returns a clonable iterator over a snapshot of the values in order
+func (m *OrderedMap[K, V]) Values() Iter[V] {
+	values := make([]V, 0, len(m.items))
+	for e := m.head; e != nil; e = e.next {
+		values = append(values, e.Value)
+	}
+	return Slice(values...).AsIter()
+}
+
+// Entries returns a clonable iterator over a snapshot of the entries in order
+func (m *OrderedMap[K, V]) Entries() Iter[Entry[K, V]] {
+	entries := make([]Entry[K, V], 0, len(m.items))
+	for e := m.head; e != nil; e = e.next {
+		entries = append(entries, e.Entry)
+	}
+	return Slice(entries...).AsIter()
+}
+
+// MarshalJSON encodes the map as a JSON object with the keys in order.
+// The keys follow the rules of encoding/json: strings, integers or encoding.TextMarshaler implementations.
+func (m OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for e := m.head; e != nil; e = e.next {
+		if e != m.head {
+			buf.WriteByte(',')
+		}
+		key, err := encodeMapKey(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		kb, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+
+		vb, err := json.Marshal(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON replaces the entries of the map with the members of a JSON object, in the order they appear
+func (m *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil // like encoding/json, null leaves the map untouched
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("cannot unmarshal %v into an ordered map", tok)
+	}
+
+	m.items, m.head, m.tail = nil, nil, nil
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var key K
+		if err := decodeMapKey(tok.(string), &key); err != nil {
+			return err
+		}
+		var value V
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		m.Put(key, value)
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func (m *OrderedMap[K, V]) pushBack(e *orderedEntry[K, V]) {
+	e.prev, e.next = m.tail, nil
+	if m.tail == nil {
+		m.head = e
+	} else {
+		m.tail.next = e
+	}
+	m.tail = e
+}
+
+func (m *OrderedMap[K, V]) unlink(e *orderedEntry[K, V]) {
+	if e.prev == nil {
+		m.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		m.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+	e.prev, e.next = nil, nil
+}
+
+func (m *OrderedMap[K, V]) moveToBack(e *orderedEntry[K, V]) {
+	if m.tail == e {
+		return
+	}
+	m.unlink(e)
+	m.pushBack(e)
+}
+
+func encodeMapKey(key any) (string, error) {
+	rv := reflect.ValueOf(key)
+	if rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+	if tm, ok := key.(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %T", key)
+}
+
+func decodeMapKey[K any](s string, key *K) error {
+	rv := reflect.ValueOf(key).Elem()
+	if rv.Kind() == reflect.String {
+		rv.SetString(s)
+		return nil
+	}
+	if tu, ok := any(key).(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(s))
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || rv.OverflowInt(n) {
+			return fmt.Errorf("invalid map key %q for %T", s, *key)
+		}
+		rv.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || rv.OverflowUint(n) {
+			return fmt.Errorf("invalid map key %q for %T", s, *key)
+		}
+		rv.SetUint(n)
+		return nil
+	}
+	return fmt.Errorf("unsupported map key type %T", *key)
+}
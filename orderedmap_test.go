@@ -0,0 +1,148 @@
+package hie
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap[string, int]
+	_, ok := m.Get("a")
+	require.False(t, ok)
+	_, ok = m.Oldest()
+	require.False(t, ok)
+
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+	m.Put("a", 10)
+	require.Equal(t, 3, m.Len())
+	require.Equal(t, []string{"c", "a", "b"}, collect(m.Keys()))
+	require.Equal(t, []int{3, 10, 2}, collect(m.Values()))
+	require.Equal(t, []Entry[string, int]{{"c", 3}, {"a", 10}, {"b", 2}}, collect(m.Entries()))
+
+	v, ok := m.Get("c")
+	require.True(t, ok)
+	require.Equal(t, 3, v)
+	require.Equal(t, []string{"c", "a", "b"}, collect(m.Keys()))
+
+	require.True(t, m.Delete("a"))
+	require.False(t, m.Delete("a"))
+	require.False(t, m.Has("a"))
+	require.Equal(t, []string{"c", "b"}, collect(m.Keys()))
+
+	oldest, ok := m.RemoveOldest()
+	require.True(t, ok)
+	require.Equal(t, Entry[string, int]{"c", 3}, oldest)
+	require.True(t, m.Delete("b"))
+	require.Zero(t, m.Len())
+	require.Empty(t, collect(m.Keys()))
+
+	keys := NewOrderedMap[string, int]()
+	keys.Put("x", 1)
+	it := keys.Keys()
+	cl := it.(ClonableIter[string]).Clone()
+	require.Equal(t, []string{"x"}, collect(it))
+	require.Equal(t, []string{"x"}, collect(cl))
+
+	require.Panics(t, func() { NewOrderedMap[string, int](OrderedMapOptions{}, OrderedMapOptions{}) })
+}
+
+func TestOrderedMap_AccessOrder(t *testing.T) {
+	m := NewOrderedMap[int, string](OrderedMapOptions{AccessOrder: true})
+	m.Put(1, "a")
+	m.Put(2, "b")
+	m.Put(3, "c")
+
+	_, _ = m.Get(1)
+	m.Put(2, "B")
+	_, _ = m.Peek(3)
+	require.Equal(t, []int{3, 1, 2}, collect(m.Keys()))
+
+	lru, ok := m.RemoveOldest()
+	require.True(t, ok)
+	require.Equal(t, 3, lru.Key)
+}
+
+type point struct{ X, Y int }
+
+func (p point) MarshalText() ([]byte, error) {
+	return json.Marshal([]int{p.X, p.Y})
+}
+
+func (p *point) UnmarshalText(text []byte) error {
+	var xy []int
+	if err := json.Unmarshal(text, &xy); err != nil {
+		return err
+	}
+	p.X, p.Y = xy[0], xy[1]
+	return nil
+}
+
+func TestOrderedMap_JSON(t *testing.T) {
+	m := NewOrderedMap[string, []int]()
+	m.Put("z", []int{1})
+	m.Put("a", nil)
+	m.Put("m", []int{2, 3})
+
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"z":[1],"a":null,"m":[2,3]}`, string(b))
+	require.Equal(t, `{"z":[1],"a":null,"m":[2,3]}`, string(b))
+
+	var decoded OrderedMap[string, []int]
+	decoded.Put("stale", nil)
+	require.NoError(t, json.Unmarshal([]byte(`{"z":[1],"a":null,"m":[2,3]}`), &decoded))
+	require.Equal(t, []string{"z", "a", "m"}, collect(decoded.Keys()))
+	require.Equal(t, [][]int{{1}, nil, {2, 3}}, collect(decoded.Values()))
+
+	ints := NewOrderedMap[int8, bool]()
+	require.NoError(t, json.Unmarshal([]byte(`{"5":true,"-1":false}`), ints))
+	require.Equal(t, []int8{5, -1}, collect(ints.Keys()))
+	b, err = json.Marshal(ints)
+	require.NoError(t, err)
+	require.Equal(t, `{"5":true,"-1":false}`, string(b))
+	require.Error(t, json.Unmarshal([]byte(`{"500":true}`), ints))
+	require.Error(t, json.Unmarshal([]byte(`[]`), ints))
+
+	points := NewOrderedMap[point, string]()
+	points.Put(point{1, 2}, "a")
+	b, err = json.Marshal(points)
+	require.NoError(t, err)
+	require.Equal(t, `{"[1,2]":"a"}`, string(b))
+	decodedPoints := NewOrderedMap[point, string]()
+	require.NoError(t, json.Unmarshal(b, decodedPoints))
+	require.Equal(t, []point{{1, 2}}, collect(decodedPoints.Keys()))
+
+	_, err = json.Marshal(NewOrderedMap[float64, int]())
+	require.NoError(t, err) // an empty map has no keys to encode
+	floats := NewOrderedMap[float64, int]()
+	floats.Put(1.5, 1)
+	_, err = json.Marshal(floats)
+	require.Error(t, err)
+}
+
+func TestOrderedMap_JSONByValue(t *testing.T) {
+	var m OrderedMap[string, int]
+	m.Put("b", 2)
+	m.Put("a", 1)
+
+	holder := struct {
+		M OrderedMap[string, int]
+	}{M: m}
+	b, err := json.Marshal(holder)
+	require.NoError(t, err)
+	require.Equal(t, `{"M":{"b":2,"a":1}}`, string(b))
+
+	b, err = json.Marshal(map[string]OrderedMap[string, int]{"m": m})
+	require.NoError(t, err)
+	require.Equal(t, `{"m":{"b":2,"a":1}}`, string(b))
+
+	var decoded struct {
+		M OrderedMap[string, int]
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"M":{"b":2,"a":1}}`), &decoded))
+	require.Equal(t, []string{"b", "a"}, collect(decoded.M.Keys()))
+}
@@ -0,0 +1,179 @@
+package hie
+
+// NewSet creates a set with the provided values
+func NewSet[T comparable](values ...T) *Set[T] {
+	s := &Set[T]{items: make(map[T]struct{}, len(values))}
+	s.Add(values...)
+	return s
+}
+
+// SetFromIter creates a set with the elements of the iterator
+func SetFromIter[T comparable](iter Iter[T]) *Set[T] {
+	s := NewSet[T]()
+	for iter.HasNext() {
+		s.Add(iter.Next())
+	}
+	return s
+}
+
+// Set is a collection of unique values, the zero value is an empty set.
+// The methods ending in With modify the set in place, the other set operations return a new set.
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// Add adds the values to the set
+func (s *Set[T]) Add(values ...T) {
+	if s.items == nil {
+		s.items = make(map[T]struct{}, len(values))
+	}
+	for _, v := range values {
+		s.items[v] = struct{}{}
+	}
+}
+
+// Remove removes the values from the set
+func (s *Set[T]) Remove(values ...T) {
+	for _, v := range values {
+		delete(s.items, v)
+	}
+}
+
+// Has returns true when the value is in the set
+func (s *Set[T]) Has(value T) bool {
+	_, ok := s.items[value]
+	return ok
+}
+
+// Len returns the number of values in the set
+func (s *Set[T]) Len() int {
+	return len(s.items)
+}
+
+// Clone returns a copy of the set
+func (s *Set[T]) Clone() *Set[T] {
+	res := &Set[T]{items: make(map[T]struct{}, len(s.items))}
+	for v := range s.items {
+		res.items[v] = struct{}{}
+	}
+	return res
+}
+
+// AsIter returns a clonable iterator over a snapshot of the values, in no particular order
+func (s *Set[T]) AsIter() Iter[T] {
+	values := make([]T, 0, len(s.items))
+	for v := range s.items {
+		values = append(values, v)
+	}
+	return Slice(values...).AsIter()
+}
+
+// Equal returns true when both sets contain the same values
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// IsSubset returns true when all values of this set are contained in the other set
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for v := range s.items {
+		if !other.Has(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDisjoint returns true when the sets have no values in common
+func (s *Set[T]) IsDisjoint(other *Set[T]) bool {
+	small, large := s, other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
+	for v := range small.items {
+		if large.Has(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new set with the values that are in either set
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	res := s.Clone()
+	res.UnionWith(other)
+	return res
+}
+
+// Intersect returns a new set with the values that are in both sets
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	small, large := s, other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
+	res := NewSet[T]()
+	for v := range small.items {
+		if large.Has(v) {
+			res.items[v] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Difference returns a new set with the values of this set that are not in the other set
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	res := NewSet[T]()
+	for v := range s.items {
+		if !other.Has(v) {
+			res.items[v] = struct{}{}
+		}
+	}
+	return res
+}
+
+// SymmetricDifference returns a new set with the values that are in exactly one of the sets
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	res := s.Difference(other)
+	for v := range other.items {
+		if !s.Has(v) {
+			res.items[v] = struct{}{}
+		}
+	}
+	return res
+}
+
+// UnionWith adds the values of the other set to this set
+func (s *Set[T]) UnionWith(other *Set[T]) {
+	for v := range other.items {
+		s.Add(v)
+	}
+}
+
+// IntersectWith removes the values that are not in the other set from this set
+func (s *Set[T]) IntersectWith(other *Set[T]) {
+	for v := range s.items {
+		if !other.Has(v) {
+			delete(s.items, v)
+		}
+	}
+}
+
+// DifferenceWith removes the values of the other set from this set
+func (s *Set[T]) DifferenceWith(other *Set[T]) {
+	for v := range other.items {
+		delete(s.items, v)
+	}
+}
+
+// SymmetricDifferenceWith keeps only the values that are in exactly one of the sets in this set
+func (s *Set[T]) SymmetricDifferenceWith(other *Set[T]) {
+	for v := range other.items {
+		if s.Has(v) {
+			delete(s.items, v)
+		} else {
+			s.Add(v)
+		}
+	}
+}
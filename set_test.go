@@ -0,0 +1,71 @@
+package hie
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sortedValues(s *Set[int]) []int {
+	values := collect(s.AsIter())
+	sort.Ints(values)
+	return values
+}
+
+func TestSet(t *testing.T) {
+	var s Set[int]
+	require.Zero(t, s.Len())
+	require.False(t, s.Has(1))
+	s.Remove(1)
+
+	s.Add(1, 2, 2, 3)
+	require.Equal(t, 3, s.Len())
+	require.True(t, s.Has(2))
+	s.Remove(2, 4)
+	require.False(t, s.Has(2))
+	require.Equal(t, []int{1, 3}, sortedValues(&s))
+
+	cl := s.Clone()
+	cl.Add(5)
+	require.False(t, s.Has(5))
+
+	it := SetFromIter(Slice(1, 1, 2).AsIter())
+	require.Equal(t, []int{1, 2}, sortedValues(it))
+	require.True(t, NewSet(2, 1).Equal(it))
+	require.False(t, NewSet(1, 3).Equal(it))
+}
+
+func TestSet_Algebra(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	b := NewSet(3, 4)
+
+	require.Equal(t, []int{1, 2, 3, 4}, sortedValues(a.Union(b)))
+	require.Equal(t, []int{3}, sortedValues(a.Intersect(b)))
+	require.Equal(t, []int{1, 2}, sortedValues(a.Difference(b)))
+	require.Equal(t, []int{1, 2, 4}, sortedValues(a.SymmetricDifference(b)))
+	require.Equal(t, []int{1, 2, 3}, sortedValues(a))
+	require.Equal(t, []int{3, 4}, sortedValues(b))
+
+	require.True(t, NewSet(1, 2).IsSubset(a))
+	require.True(t, NewSet[int]().IsSubset(a))
+	require.False(t, b.IsSubset(a))
+	require.True(t, NewSet(5).IsDisjoint(a))
+	require.False(t, b.IsDisjoint(a))
+}
+
+func TestSet_InPlace(t *testing.T) {
+	s := NewSet(1, 2, 3)
+	s.UnionWith(NewSet(4))
+	require.Equal(t, []int{1, 2, 3, 4}, sortedValues(s))
+	s.IntersectWith(NewSet(2, 3, 4, 5))
+	require.Equal(t, []int{2, 3, 4}, sortedValues(s))
+	s.DifferenceWith(NewSet(4))
+	require.Equal(t, []int{2, 3}, sortedValues(s))
+	s.SymmetricDifferenceWith(NewSet(3, 6))
+	require.Equal(t, []int{2, 6}, sortedValues(s))
+
+	var zero Set[int]
+	zero.UnionWith(NewSet(1))
+	require.True(t, zero.Has(1))
+}
@@ -15,6 +15,15 @@ type Iter[T any] interface {
 	Next() T
 }
 
+// collect drains the iterator into a slice
+func collect[T any](iter Iter[T]) []T {
+	var res []T
+	for iter.HasNext() {
+		res = append(res, iter.Next())
+	}
+	return res
+}
+
 type SliceAsIter[T any] []T
 
 func (s SliceAsIter[T]) AsIter() Iter[T] {
@@ -1,27 +1,317 @@
 package hie
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// ErrTryLockUnsupported is returned by TryDo when the locker of the synchronizer has no TryLock method
+var ErrTryLockUnsupported = errors.New("the locker does not support TryLock")
+
+// RWLocker is a sync.Locker that also provides a shared lock for readers, like sync.RWMutex
+type RWLocker interface {
+	sync.Locker
+	RLock()
+	RUnlock()
+}
+
+type tryLocker interface {
+	TryLock() bool
+}
+
+type tryRLocker interface {
+	TryRLock() bool
+}
+
+type rContextLocker interface {
+	RLockContext(ctx context.Context) error
+}
+
+// Synchronize creates a Synchronizer that uses the provided locker, or an RWLock when none is provided.
+// When the locker is an RWLocker the read operations of the Synchronizer use its shared lock.
 func Synchronize(locker ...sync.Locker) *Synchronizer {
 	if len(locker) > 1 {
 		panic("only 1 locker can be specified")
 	}
 	holder := &Synchronizer{}
-	if len(locker) == 0 {
-		holder.mu = new(sync.Mutex)
+	if len(locker) == 0 || locker[0] == nil {
+		holder.mu = NewRWLock()
+	} else {
+		holder.mu = locker[0]
 	}
 	return holder
 }
 
+// SyncStats describes the contention on the lock of a Synchronizer
+type SyncStats struct {
+	// Acquisitions is the number of times the lock was acquired
+	Acquisitions int64
+	// Contended is the number of acquisitions that had to wait for the lock.
+	// Lockers without TryLock can't be checked for availability, so each of their acquisitions counts.
+	Contended int64
+	// TotalWait is the time spent waiting for the lock over all acquisitions
+	TotalWait time.Duration
+	// MaxWait is the longest time a single acquisition waited for the lock
+	MaxWait time.Duration
+	// Holders is the number of callers that hold the lock right now, readers included
+	Holders int64
+	// Waiters is the number of callers that are waiting for the lock right now
+	Waiters int64
+}
+
 type Synchronizer struct {
 	mu sync.Locker
+
+	acquisitions atomic.Int64
+	contended    atomic.Int64
+	totalWait    atomic.Int64
+	maxWait      atomic.Int64
+	holders      atomic.Int64
+	waiters      atomic.Int64
 }
 
 func (l *Synchronizer) Do(thunk func() error) error {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	_ = l.acquire(context.Background(), false)
+	defer l.release(false)
+
+	return thunk()
+}
+
+// DoContext runs the thunk while holding the exclusive lock.
+// It returns the error of the context without running the thunk when the context is done before the lock is acquired.
+func (l *Synchronizer) DoContext(ctx context.Context, thunk func() error) error {
+	if err := l.acquire(ctx, false); err != nil {
+		return err
+	}
+	defer l.release(false)
+
+	return thunk()
+}
+
+// TryDo runs the thunk only when the exclusive lock can be acquired without waiting, it reports whether the thunk ran.
+// It returns ErrTryLockUnsupported when the locker has no TryLock method.
+func (l *Synchronizer) TryDo(thunk func() error) (bool, error) {
+	tl, ok := l.mu.(tryLocker)
+	if !ok {
+		return false, ErrTryLockUnsupported
+	}
+	if !tl.TryLock() {
+		return false, nil
+	}
+	l.acquired(0, false)
+	defer l.release(false)
+
+	return true, thunk()
+}
+
+// DoRead runs the thunk while holding the shared lock, or the exclusive lock when the locker has no shared lock
+func (l *Synchronizer) DoRead(thunk func() error) error {
+	_ = l.acquire(context.Background(), true)
+	defer l.release(true)
 
 	return thunk()
 }
+
+// Stats returns a snapshot of the contention on the lock, which is useful when debugging
+func (l *Synchronizer) Stats() SyncStats {
+	return SyncStats{
+		Acquisitions: l.acquisitions.Load(),
+		Contended:    l.contended.Load(),
+		TotalWait:    time.Duration(l.totalWait.Load()),
+		MaxWait:      time.Duration(l.maxWait.Load()),
+		Holders:      l.holders.Load(),
+		Waiters:      l.waiters.Load(),
+	}
+}
+
+func (l *Synchronizer) shared(shared bool) bool {
+	if !shared {
+		return false
+	}
+	_, ok := l.mu.(RWLocker)
+	return ok
+}
+
+func (l *Synchronizer) acquire(ctx context.Context, shared bool) error {
+	shared = l.shared(shared)
+	if l.tryLock(shared) {
+		l.acquired(0, false)
+		return nil
+	}
+
+	l.waiters.Add(1)
+	start := time.Now()
+	err := l.lockContext(ctx, shared)
+	l.waiters.Add(-1)
+	if err != nil {
+		return err
+	}
+	l.acquired(time.Since(start), true)
+	return nil
+}
+
+func (l *Synchronizer) acquired(wait time.Duration, contended bool) {
+	l.acquisitions.Add(1)
+	l.holders.Add(1)
+	if !contended {
+		return
+	}
+	l.contended.Add(1)
+	l.totalWait.Add(int64(wait))
+	for {
+		max := l.maxWait.Load()
+		if int64(wait) <= max || l.maxWait.CompareAndSwap(max, int64(wait)) {
+			return
+		}
+	}
+}
+
+func (l *Synchronizer) release(shared bool) {
+	l.holders.Add(-1)
+	if l.shared(shared) {
+		l.mu.(RWLocker).RUnlock()
+		return
+	}
+	l.mu.Unlock()
+}
+
+// tryLock acquires the lock without waiting when the locker supports it, like sync.Mutex and sync.RWMutex do
+func (l *Synchronizer) tryLock(shared bool) bool {
+	if shared {
+		tl, ok := l.mu.(tryRLocker)
+		return ok && tl.TryRLock()
+	}
+	tl, ok := l.mu.(tryLocker)
+	return ok && tl.TryLock()
+}
+
+func (l *Synchronizer) lockContext(ctx context.Context, shared bool) error {
+	lock, unlock := l.mu.Lock, l.mu.Unlock
+	if shared {
+		rw := l.mu.(RWLocker)
+		lock, unlock = rw.RLock, rw.RUnlock
+		if cl, ok := l.mu.(rContextLocker); ok {
+			return cl.RLockContext(ctx)
+		}
+	} else if cl, ok := l.mu.(ContextLocker); ok {
+		return cl.LockContext(ctx)
+	}
+
+	if ctx.Done() == nil {
+		lock()
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// the locker can't be abandoned, so lock it in a go routine that gives it back when nobody is waiting anymore
+	var abandoned bool
+	var mu sync.Mutex
+	locked := make(chan struct{})
+	go func() {
+		lock()
+		mu.Lock()
+		defer mu.Unlock()
+		if abandoned {
+			unlock()
+			return
+		}
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		return nil
+	case <-ctx.Done():
+		mu.Lock()
+		defer mu.Unlock()
+		select {
+		case <-locked:
+			return nil
+		default:
+			abandoned = true
+			return ctx.Err()
+		}
+	}
+}
+
+// DoValue runs the thunk while holding the exclusive lock of the synchronizer and returns its result
+func DoValue[R any](l *Synchronizer, thunk func() (R, error)) (R, error) {
+	var res R
+	err := l.Do(func() error {
+		var err error
+		res, err = thunk()
+		return err
+	})
+	return res, err
+}
+
+// DoReadValue runs the thunk while holding the shared lock of the synchronizer and returns its result
+func DoReadValue[R any](l *Synchronizer, thunk func() (R, error)) (R, error) {
+	var res R
+	err := l.DoRead(func() error {
+		var err error
+		res, err = thunk()
+		return err
+	})
+	return res, err
+}
+
+// Guard creates a Guarded value that is protected by the provided locker, or an RWLock when none is provided
+func Guard[T any](value T, locker ...sync.Locker) *Guarded[T] {
+	return &Guarded[T]{
+		sync:  Synchronize(locker...),
+		value: value,
+	}
+}
+
+// Guarded holds a value that can only be accessed while holding a lock
+type Guarded[T any] struct {
+	sync  *Synchronizer
+	value T
+}
+
+// Read calls the function with the value while holding the shared lock
+func (g *Guarded[T]) Read(fn func(T)) {
+	_ = g.sync.DoRead(func() error {
+		fn(g.value)
+		return nil
+	})
+}
+
+// Update calls the function with a pointer to the value while holding the exclusive lock
+func (g *Guarded[T]) Update(fn func(*T) error) error {
+	return g.sync.Do(func() error {
+		return fn(&g.value)
+	})
+}
+
+// Load returns the value
+func (g *Guarded[T]) Load() T {
+	res, _ := DoReadValue(g.sync, func() (T, error) {
+		return g.value, nil
+	})
+	return res
+}
+
+// Store replaces the value
+func (g *Guarded[T]) Store(value T) {
+	_ = g.sync.Do(func() error {
+		g.value = value
+		return nil
+	})
+}
+
+// Swap replaces the value and returns the previous one
+func (g *Guarded[T]) Swap(value T) T {
+	res, _ := DoValue(g.sync, func() (T, error) {
+		prev := g.value
+		g.value = value
+		return prev, nil
+	})
+	return res
+}
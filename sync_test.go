@@ -0,0 +1,251 @@
+package hie
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingLocker struct {
+	sync.Mutex
+	locks int
+}
+
+func (c *countingLocker) Lock() {
+	c.Mutex.Lock()
+	c.locks++
+}
+
+func (c *countingLocker) TryLock() bool {
+	if !c.Mutex.TryLock() {
+		return false
+	}
+	c.locks++
+	return true
+}
+
+type countingRWLocker struct {
+	sync.RWMutex
+	locks  int
+	rlocks int
+	mu     sync.Mutex
+}
+
+func (c *countingRWLocker) Lock() {
+	c.RWMutex.Lock()
+	c.locks++
+}
+
+func (c *countingRWLocker) TryLock() bool {
+	if !c.RWMutex.TryLock() {
+		return false
+	}
+	c.locks++
+	return true
+}
+
+func (c *countingRWLocker) RLock() {
+	c.RWMutex.RLock()
+	c.mu.Lock()
+	c.rlocks++
+	c.mu.Unlock()
+}
+
+func (c *countingRWLocker) TryRLock() bool {
+	if !c.RWMutex.TryRLock() {
+		return false
+	}
+	c.mu.Lock()
+	c.rlocks++
+	c.mu.Unlock()
+	return true
+}
+
+func TestSynchronize_DefaultLocker(t *testing.T) {
+	s := Synchronize()
+	_, isRW := s.mu.(*RWLock)
+	require.True(t, isRW)
+
+	exp := errors.New("expected")
+	require.Equal(t, exp, s.Do(func() error { return exp }))
+	require.NoError(t, s.DoRead(func() error { return nil }))
+}
+
+func TestSynchronize_CustomLocker(t *testing.T) {
+	locker := &countingLocker{}
+	s := Synchronize(locker)
+	require.NoError(t, s.Do(func() error { return nil }))
+	require.NoError(t, s.DoRead(func() error { return nil }))
+	require.Equal(t, 2, locker.locks)
+
+	rw := &countingRWLocker{}
+	s = Synchronize(rw)
+	require.NoError(t, s.Do(func() error { return nil }))
+	require.NoError(t, s.DoRead(func() error { return nil }))
+	require.Equal(t, 1, rw.locks)
+	require.Equal(t, 1, rw.rlocks)
+
+	require.Panics(t, func() { Synchronize(locker, rw) })
+}
+
+func TestDoValue(t *testing.T) {
+	s := Synchronize()
+	v, err := DoValue(s, func() (string, error) { return "value", nil })
+	require.NoError(t, err)
+	require.Equal(t, "value", v)
+
+	exp := errors.New("expected")
+	_, err = DoReadValue(s, func() (int, error) { return 0, exp })
+	require.Equal(t, exp, err)
+}
+
+func TestGuarded(t *testing.T) {
+	g := Guard(map[string]int{})
+
+	var wg sync.WaitGroup
+	wg.Add(100)
+	for i := 0; i < 100; i++ {
+		go func() {
+			defer wg.Done()
+			_ = g.Update(func(m *map[string]int) error {
+				(*m)["count"]++
+				return nil
+			})
+			g.Read(func(m map[string]int) { _ = m["count"] })
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 100, g.Load()["count"])
+
+	exp := errors.New("expected")
+	require.Equal(t, exp, g.Update(func(*map[string]int) error { return exp }))
+
+	prev := g.Swap(map[string]int{"count": 1})
+	require.Equal(t, 100, prev["count"])
+	g.Store(nil)
+	require.Nil(t, g.Load())
+}
+
+func TestGuarded_CustomLocker(t *testing.T) {
+	rw := &countingRWLocker{}
+	g := Guard(1, rw)
+	g.Store(2)
+	require.Equal(t, 2, g.Load())
+	require.Equal(t, 1, rw.locks)
+	require.Equal(t, 1, rw.rlocks)
+}
+
+type plainLocker struct {
+	mu chan struct{}
+}
+
+func (p *plainLocker) Lock()   { p.mu <- struct{}{} }
+func (p *plainLocker) Unlock() { <-p.mu }
+
+func TestSynchronizer_DoContext(t *testing.T) {
+	s := Synchronize()
+	require.NoError(t, s.DoContext(context.Background(), func() error { return nil }))
+
+	release := make(chan struct{})
+	go func() {
+		_ = s.Do(func() error {
+			<-release
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool { return s.Stats().Holders == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var ran bool
+	err := s.DoContext(ctx, func() error {
+		ran = true
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.False(t, ran)
+
+	done := make(chan error)
+	go func() { done <- s.DoContext(context.Background(), func() error { return nil }) }()
+	require.Eventually(t, func() bool { return s.Stats().Waiters == 1 }, time.Second, time.Millisecond)
+	close(release)
+	require.NoError(t, <-done)
+
+	stats := s.Stats()
+	require.EqualValues(t, 3, stats.Acquisitions)
+	require.EqualValues(t, 1, stats.Contended)
+	require.Greater(t, stats.TotalWait, time.Duration(0))
+	require.Equal(t, stats.TotalWait, stats.MaxWait)
+	require.Zero(t, stats.Holders)
+	require.Zero(t, stats.Waiters)
+}
+
+func TestSynchronizer_DoContextPlainLocker(t *testing.T) {
+	locker := &plainLocker{mu: make(chan struct{}, 1)}
+	s := Synchronize(locker)
+
+	locker.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, s.DoContext(ctx, func() error { return nil }), context.DeadlineExceeded)
+	locker.Unlock()
+
+	// the abandoned acquisition gives the lock back
+	require.NoError(t, s.DoContext(context.Background(), func() error { return nil }))
+	ok, err := s.TryDo(func() error { return nil })
+	require.ErrorIs(t, err, ErrTryLockUnsupported)
+	require.False(t, ok)
+}
+
+func TestSynchronizer_TryDo(t *testing.T) {
+	s := Synchronize(&sync.Mutex{})
+	exp := errors.New("expected")
+	ok, err := s.TryDo(func() error { return exp })
+	require.True(t, ok)
+	require.Equal(t, exp, err)
+
+	ok, err = s.TryDo(func() error {
+		nested, err := s.TryDo(func() error { return nil })
+		require.False(t, nested)
+		return err
+	})
+	require.True(t, ok)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, s.Stats().Acquisitions)
+}
+
+func TestSynchronizer_StatsStdLockers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, locker := range []sync.Locker{&sync.Mutex{}, &sync.RWMutex{}} {
+		s := Synchronize(locker)
+		require.NoError(t, s.DoContext(ctx, func() error { return nil }))
+		require.NoError(t, s.DoRead(func() error { return nil }))
+		require.NoError(t, s.Do(func() error { return nil }))
+
+		stats := s.Stats()
+		require.EqualValues(t, 3, stats.Acquisitions)
+		require.Zero(t, stats.Contended)
+
+		release := make(chan struct{})
+		go func() {
+			_ = s.Do(func() error {
+				<-release
+				return nil
+			})
+		}()
+		require.Eventually(t, func() bool { return s.Stats().Holders == 1 }, time.Second, time.Millisecond)
+
+		done := make(chan error)
+		go func() { done <- s.DoContext(ctx, func() error { return nil }) }()
+		require.Eventually(t, func() bool { return s.Stats().Waiters == 1 }, time.Second, time.Millisecond)
+		close(release)
+		require.NoError(t, <-done)
+		require.EqualValues(t, 1, s.Stats().Contended)
+	}
+}
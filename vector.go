@@ -0,0 +1,183 @@
+package hie
+
+const (
+	vectorBits  = 5
+	vectorWidth = 1 << vectorBits
+	vectorMask  = vectorWidth - 1
+)
+
+// VectorOf creates a persistent vector with the provided values
+func VectorOf[T any](values ...T) Vector[T] {
+	var v Vector[T]
+	for _, value := range values {
+		v = v.Append(value)
+	}
+	return v
+}
+
+// Vector is an immutable indexed sequence, every modification returns a new vector that shares most of its structure with the original.
+// The elements are stored in a 32-way trie, so Get, Set and Append take effectively constant time.
+// Vectors can be shared between go routines without copying, the zero value is an empty vector.
+type Vector[T any] struct {
+	size  int
+	shift uint
+	root  *vectorNode[T]
+	tail  []T
+}
+
+type vectorNode[T any] struct {
+	children []*vectorNode[T]
+	values   []T
+}
+
+// Len returns the number of elements in the vector
+func (v Vector[T]) Len() int { return v.size }
+
+// IsEmpty returns true when the vector has no elements
+func (v Vector[T]) IsEmpty() bool { return v.size == 0 }
+
+// Get returns the element at the index, it panics when the index is out of range
+func (v Vector[T]) Get(index int) T {
+	v.check(index)
+	return v.leaf(index)[index&vectorMask]
+}
+
+// Append returns a new vector with the value at the end
+func (v Vector[T]) Append(value T) Vector[T] {
+	if v.size-v.tailOffset() < vectorWidth {
+		tail := make([]T, len(v.tail), len(v.tail)+1)
+		copy(tail, v.tail)
+		return Vector[T]{size: v.size + 1, shift: v.shift, root: v.root, tail: append(tail, value)}
+	}
+
+	// the tail is full, it moves into the trie
+	full := &vectorNode[T]{values: v.tail}
+	root, shift := v.root, v.shift
+	switch {
+	case root == nil:
+		root, shift = &vectorNode[T]{children: make([]*vectorNode[T], vectorWidth)}, vectorBits
+		root.children[0] = full
+	case v.size>>vectorBits > 1<<shift:
+		root = &vectorNode[T]{children: make([]*vectorNode[T], vectorWidth)}
+		root.children[0] = v.root
+		root.children[1] = newVectorPath(shift, full)
+		shift += vectorBits
+	default:
+		root = v.pushTail(shift, root, full)
+	}
+	return Vector[T]{size: v.size + 1, shift: shift, root: root, tail: []T{value}}
+}
+
+// Prepend returns a new vector with the value in front, this copies all the elements of the vector
+func (v Vector[T]) Prepend(value T) Vector[T] {
+	res := Vector[T]{}.Append(value)
+	it := v.AsIter()
+	for it.HasNext() {
+		res = res.Append(it.Next())
+	}
+	return res
+}
+
+// Set returns a new vector with the value at the index, it panics when the index is out of range
+func (v Vector[T]) Set(index int, value T) Vector[T] {
+	v.check(index)
+	if index >= v.tailOffset() {
+		tail := make([]T, len(v.tail))
+		copy(tail, v.tail)
+		tail[index&vectorMask] = value
+		return Vector[T]{size: v.size, shift: v.shift, root: v.root, tail: tail}
+	}
+	return Vector[T]{size: v.size, shift: v.shift, root: v.assoc(v.shift, v.root, index, value), tail: v.tail}
+}
+
+// AsIter returns a clonable iterator over the elements of the vector
+func (v Vector[T]) AsIter() Iter[T] {
+	return &vectorIter[T]{vec: v}
+}
+
+func (v Vector[T]) check(index int) {
+	if index < 0 || index >= v.size {
+		panic("index out of range")
+	}
+}
+
+// tailOffset is the index of the first element in the tail
+func (v Vector[T]) tailOffset() int {
+	if v.size < vectorWidth {
+		return 0
+	}
+	return ((v.size - 1) >> vectorBits) << vectorBits
+}
+
+// leaf returns the values of the leaf that holds the index
+func (v Vector[T]) leaf(index int) []T {
+	if index >= v.tailOffset() {
+		return v.tail
+	}
+	node := v.root
+	for level := v.shift; level > 0; level -= vectorBits {
+		node = node.children[(index>>level)&vectorMask]
+	}
+	return node.values
+}
+
+func (v Vector[T]) pushTail(level uint, parent, tail *vectorNode[T]) *vectorNode[T] {
+	res := &vectorNode[T]{children: append([]*vectorNode[T](nil), parent.children...)}
+	sub := ((v.size - 1) >> level) & vectorMask
+	switch {
+	case level == vectorBits:
+		res.children[sub] = tail
+	case parent.children[sub] != nil:
+		res.children[sub] = v.pushTail(level-vectorBits, parent.children[sub], tail)
+	default:
+		res.children[sub] = newVectorPath(level-vectorBits, tail)
+	}
+	return res
+}
+
+func (v Vector[T]) assoc(level uint, node *vectorNode[T], index int, value T) *vectorNode[T] {
+	if level == 0 {
+		values := append([]T(nil), node.values...)
+		values[index&vectorMask] = value
+		return &vectorNode[T]{values: values}
+	}
+	res := &vectorNode[T]{children: append([]*vectorNode[T](nil), node.children...)}
+	sub := (index >> level) & vectorMask
+	res.children[sub] = v.assoc(level-vectorBits, node.children[sub], index, value)
+	return res
+}
+
+func newVectorPath[T any](level uint, node *vectorNode[T]) *vectorNode[T] {
+	if level == 0 {
+		return node
+	}
+	res := &vectorNode[T]{children: make([]*vectorNode[T], vectorWidth)}
+	res.children[0] = newVectorPath(level-vectorBits, node)
+	return res
+}
+
+type vectorIter[T any] struct {
+	vec   Vector[T]
+	idx   int
+	block []T
+}
+
+func (v *vectorIter[T]) HasNext() bool {
+	return v.idx < v.vec.size
+}
+
+func (v *vectorIter[T]) Next() T {
+	if !v.HasNext() {
+		panic("iterating beyond end")
+	}
+	if v.idx&vectorMask == 0 || v.block == nil {
+		v.block = v.vec.leaf(v.idx)
+	}
+	res := v.block[v.idx&vectorMask]
+	v.idx++
+	return res
+}
+
+func (v *vectorIter[T]) Clone() Iter[T] {
+	return &vectorIter[T]{vec: v.vec, idx: v.idx, block: v.block}
+}
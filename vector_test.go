@@ -0,0 +1,72 @@
+package hie
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVector(t *testing.T) {
+	var empty Vector[int]
+	require.True(t, empty.IsEmpty())
+	require.Empty(t, collect(empty.AsIter()))
+	require.Panics(t, func() { empty.Get(0) })
+
+	v := VectorOf(1, 2, 3)
+	require.Equal(t, 3, v.Len())
+	require.Equal(t, []int{0, 1, 2, 3}, collect(v.Prepend(0).AsIter()))
+	require.Equal(t, []int{1, 2, 3, 4}, collect(v.Append(4).AsIter()))
+	require.Equal(t, []int{1, 20, 3}, collect(v.Set(1, 20).AsIter()))
+	require.Equal(t, []int{1, 2, 3}, collect(v.AsIter()))
+	require.Panics(t, func() { v.Set(3, 0) })
+}
+
+func TestVector_Persistent(t *testing.T) {
+	const size = 40000 // deep enough for a trie of 3 levels below the root
+	rnd := rand.New(rand.NewSource(1))
+
+	var versions []Vector[int]
+	var models [][]int
+	var v Vector[int]
+	var model []int
+	for i := 0; i < size; i++ {
+		v = v.Append(i)
+		model = append(model, i)
+		if rnd.Intn(1000) == 0 {
+			idx := rnd.Intn(len(model))
+			v = v.Set(idx, -i)
+			model = append([]int(nil), model...)
+			model[idx] = -i
+			versions = append(versions, v)
+			models = append(models, append([]int(nil), model...))
+		}
+	}
+	versions = append(versions, v)
+	models = append(models, model)
+
+	for i, version := range versions {
+		require.Equal(t, len(models[i]), version.Len())
+		require.Equal(t, models[i], collect(version.AsIter()))
+		for j := 0; j < 100; j++ {
+			idx := rnd.Intn(version.Len())
+			require.Equal(t, models[i][idx], version.Get(idx))
+		}
+	}
+}
+
+func TestVector_AsIterClone(t *testing.T) {
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = i
+	}
+	it := VectorOf(values...).AsIter()
+	for i := 0; i < 50; i++ {
+		it.Next()
+	}
+
+	cl := it.(ClonableIter[int]).Clone()
+	require.Equal(t, values[50:], collect(it))
+	require.Equal(t, values[50:], collect(cl))
+	require.Panics(t, func() { it.Next() })
+}